@@ -1,7 +1,7 @@
 /*
  * areainfo.go
  *
- * Copyright 2021-2023 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2021-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -36,10 +36,10 @@ func areaInfoDialog() error {
 	if activeGame == nil {
 		return fmt.Errorf("%s\n", lang.Text("no_game_err"))
 	}
-	if activeGame.ActivePlayer() == nil {
+	if actingPlayer() == nil {
 		return fmt.Errorf("%s\n", lang.Text("no_pc_err"))
 	}
-	area := activeGame.Chapter().ObjectArea(activeGame.ActivePlayer().Character)
+	area := activeGame.Chapter().ObjectArea(actingPlayer().Character)
 	if area == nil {
 		return fmt.Errorf("%s\n", lang.Text("no_pc_area_err"))
 	}
@@ -0,0 +1,70 @@
+/*
+ * autosave.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/isangeles/burnsh/config"
+	"github.com/isangeles/burnsh/game"
+	"github.com/isangeles/burnsh/log"
+)
+
+// autosave saves specified game in the next autosave slot.
+func autosave(g *game.Game) {
+	path := filepath.Join(g.Conf().Path, ModuleSavesPath)
+	name := autosaveSlot(path, config.AutosaveSlots)
+	err := saveGameAs(g, name)
+	if err != nil {
+		log.Err.Printf("Unable to autosave game: %v", err)
+		return
+	}
+	log.Dbg.Printf("Game autosaved: %s", name)
+}
+
+// autosaveSlot returns name of the autosave slot to use
+// for the next autosave in directory with specified path.
+// Returns the first unused slot or, if all slots are used,
+// the slot with the oldest save.
+func autosaveSlot(path string, slots int) string {
+	if slots < 1 {
+		slots = 1
+	}
+	slot := ""
+	var oldest os.FileInfo
+	for i := 1; i <= slots; i++ {
+		name := fmt.Sprintf("%s%d", AutosavePrefix, i)
+		info, err := os.Stat(filepath.Join(path, name+SaveExt))
+		if err != nil {
+			return name
+		}
+		if oldest == nil || info.ModTime().Before(oldest.ModTime()) {
+			slot = name
+			oldest = info
+		}
+	}
+	return slot
+}
@@ -0,0 +1,60 @@
+/*
+ * autosave_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestAutosaveSlot tests autosave slots rotation.
+func TestAutosaveSlot(t *testing.T) {
+	dir := t.TempDir()
+	// Test empty slots.
+	slot := autosaveSlot(dir, 2)
+	if slot != AutosavePrefix+"1" {
+		t.Errorf("Autosave slot invalid: %s != %s1", slot, AutosavePrefix)
+	}
+	// Fill slots.
+	now := time.Now()
+	for i, name := range []string{"1", "2"} {
+		path := filepath.Join(dir, AutosavePrefix+name+SaveExt)
+		err := os.WriteFile(path, nil, 0644)
+		if err != nil {
+			t.Fatalf("Unable to create save file: %v", err)
+		}
+		modTime := now.Add(time.Duration(-i) * time.Hour)
+		err = os.Chtimes(path, modTime, modTime)
+		if err != nil {
+			t.Fatalf("Unable to set save file time: %v", err)
+		}
+	}
+	// Test oldest slot.
+	slot = autosaveSlot(dir, 2)
+	if slot != AutosavePrefix+"2" {
+		t.Errorf("Autosave slot invalid: %s != %s2", slot, AutosavePrefix)
+	}
+}
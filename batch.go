@@ -0,0 +1,161 @@
+/*
+ * batch.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/isangeles/burnsh/game"
+	"github.com/isangeles/burnsh/log"
+)
+
+// Game update step for simulation in batch mode, in milliseconds.
+const batchUpdateStep = 16
+
+// Struct for batch mode options.
+type batchOptions struct {
+	save    string
+	steps   []batchStep
+	simTime int64
+}
+
+// Struct for batch mode step, shell command or
+// script to execute.
+type batchStep struct {
+	command string
+	script  string
+}
+
+// Type for command line flags that add batch steps.
+// Flags can be specified multiple times, steps are
+// kept in order of the flags on the command line.
+type batchFlag struct {
+	steps  *[]batchStep
+	script bool
+}
+
+// String returns all steps of the flag type separated
+// by comma.
+func (f batchFlag) String() string {
+	if f.steps == nil {
+		return ""
+	}
+	values := make([]string, 0)
+	for _, s := range *f.steps {
+		if f.script && len(s.script) > 0 {
+			values = append(values, s.script)
+		}
+		if !f.script && len(s.command) > 0 {
+			values = append(values, s.command)
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+// Set adds step with specified value to the batch steps.
+func (f batchFlag) Set(value string) error {
+	if f.script {
+		*f.steps = append(*f.steps, batchStep{script: value})
+		return nil
+	}
+	*f.steps = append(*f.steps, batchStep{command: value})
+	return nil
+}
+
+// runBatch runs shell in batch mode with specified options.
+// Loads save, executes commands and scripts in the specified
+// order and simulates game for specified time, without user
+// input. Games started by the commands and scripts are updated
+// only by the simulation.
+// Returns number of failed commands and scripts, or error
+// if batch was unable to start.
+func runBatch(opts batchOptions) (int, error) {
+	if mod == nil {
+		return 0, fmt.Errorf("no module loaded")
+	}
+	if len(opts.save) > 0 {
+		err := batchLoadGame(opts.save)
+		if err != nil {
+			return 0, fmt.Errorf("unable to load game: %v", err)
+		}
+	}
+	fails := 0
+	for _, s := range opts.steps {
+		if len(s.script) > 0 {
+			err := executeFile(false, s.script, s.script)
+			if err != nil {
+				log.Err.Printf("Batch: script failed: %s: %v", s.script, err)
+				fails++
+			}
+			continue
+		}
+		c := s.command
+		if !strings.HasPrefix(c, CommandPrefix) {
+			c = CommandPrefix + c
+		}
+		err := handleInput(c)
+		if err != nil {
+			log.Err.Printf("Batch: command failed: %v", err)
+			fails++
+		}
+	}
+	if opts.simTime > 0 {
+		if activeGame == nil {
+			return fails, fmt.Errorf("no game to simulate")
+		}
+		simulate(activeGame, opts.simTime)
+	}
+	return fails, nil
+}
+
+// batchLoadGame loads save with specified name without
+// starting the real-time game loop.
+// In case of game server, save is loaded by the server.
+func batchLoadGame(name string) error {
+	if server != nil {
+		return loadGameDialog(name)
+	}
+	dir := filepath.Join(mod.Conf().Path, ModuleSavesPath)
+	g, cliSave, err := loadGame(dir, name)
+	if err != nil {
+		return err
+	}
+	restoreShell(cliSave)
+	setActiveGame(g)
+	return nil
+}
+
+// simulate updates specified game for specified time in
+// milliseconds, with fixed update steps.
+func simulate(g *game.Game, time int64) {
+	for t := int64(0); t < time; t += batchUpdateStep {
+		delta := int64(batchUpdateStep)
+		if time-t < delta {
+			delta = time - t
+		}
+		g.Update(delta)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * batch_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"testing"
+)
+
+// TestRunBatch tests running commands in batch mode.
+func TestRunBatch(t *testing.T) {
+	g := newTestGame("player_test")
+	g.SetActivePlayer(g.Players()[0])
+	setActiveGame(g)
+	defer func() { activeGame = nil }()
+	opts := batchOptions{
+		steps: []batchStep{
+			{command: HelpCmd},
+			{command: "unknown_test"},
+		},
+		simTime: 100,
+	}
+	fails, err := runBatch(opts)
+	if err != nil {
+		t.Fatalf("Unable to run batch: %v", err)
+	}
+	if fails != 1 {
+		t.Errorf("Failed commands number invalid: %d != 1", fails)
+	}
+}
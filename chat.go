@@ -1,7 +1,7 @@
 /*
  * chat.go
  *
- * Copyright 2020-2023 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2020-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -28,6 +28,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/isangeles/flame/data/res/lang"
@@ -51,11 +52,21 @@ func (mbt MessagesByTime) Less(i, j int) bool { return mbt[i].time.UnixNano() <
 var chatOpen bool
 
 // chatDialog starts chat CLI dialog.
-func chatDialog() error {
+// If a message is specified as arguments, the message is sent
+// to the active player chat instead of opening the chat.
+func chatDialog(args ...string) error {
 	if activeGame == nil {
 		msg := lang.Text("no_game_err")
 		return fmt.Errorf(msg)
 	}
+	if len(args) > 0 {
+		if actingPlayer() == nil {
+			msg := lang.Text("no_pc_err")
+			return fmt.Errorf(msg)
+		}
+		actingPlayer().AddChatMessage(strings.Join(args, " "))
+		return nil
+	}
 	chatOpen = true
 	go updateChat()
 	scan := bufio.NewScanner(os.Stdin)
@@ -70,30 +81,34 @@ func chatDialog() error {
 	return nil
 }
 
-// updateChat prints messages from players, nearby objects, and system
-// log on the standard out.
+// updateChat prints messages from the active player, nearby objects,
+// and system log on the standard out.
+// Messages from other player characters are not printed, each party
+// member has its own log and chat context.
 func updateChat() {
+	lastPrint := time.Now()
 	for chatOpen {
-		// Add messages from players and nearby objects.
+		pc := actingPlayer()
+		if pc == nil {
+			continue
+		}
+		// Add messages from player and nearby objects.
 		messages := make([]Message, 0)
-		for _, pc := range activeGame.Players() {
-			// PC's private messages.
-			for _, lm := range pc.Log().Messages() {
-				m := Message{
-					author: pc.ID(),
-					time:   lm.Time,
-					text:   fmt.Sprintf("%s\n", lm.String()),
-				}
-				if !lm.Translated {
-					m.text = fmt.Sprintf("%s\n", lang.Text(lm.String()))
-				}
-				messages = append(messages, m)
+		// PC's private messages.
+		for _, lm := range pc.Log().Messages() {
+			m := Message{
+				author: pc.ID(),
+				time:   lm.Time,
+				text:   fmt.Sprintf("%s\n", lm.String()),
 			}
-			// Near objects chat.
-			area := activeGame.Chapter().ObjectArea(pc.Character)
-			if area == nil {
-				continue
+			if !lm.Translated {
+				m.text = fmt.Sprintf("%s\n", lang.Text(lm.String()))
 			}
+			messages = append(messages, m)
+		}
+		// Near objects chat.
+		area := activeGame.Chapter().ObjectArea(pc.Character)
+		if area != nil {
 			charX, charY := pc.Position()
 			for _, tar := range area.NearObjects(charX, charY, pc.SightRange()) {
 				tar, ok := tar.(objects.Logger)
@@ -115,12 +130,14 @@ func updateChat() {
 		}
 		// Sort and print messages.
 		sort.Sort(MessagesByTime(messages))
+		printTime := time.Now()
 		for _, m := range messages {
-			// Skip old messages.
-			if m.time.UnixNano() < lastUpdate.UnixNano() {
+			// Skip already printed messages.
+			if m.time.UnixNano() < lastPrint.UnixNano() {
 				continue
 			}
 			fmt.Printf("%s: %s", lang.Text(m.author), m.text)
 		}
+		lastPrint = printTime
 	}
 }
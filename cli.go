@@ -28,16 +28,20 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/isangeles/flame"
 	flamedata "github.com/isangeles/flame/data"
+	"github.com/isangeles/flame/data/res/lang"
 
 	"github.com/isangeles/burn"
 	"github.com/isangeles/burn/ash"
@@ -45,9 +49,11 @@ import (
 
 	"github.com/isangeles/fire/request"
 
+	"github.com/isangeles/burnsh/command"
 	"github.com/isangeles/burnsh/config"
 	"github.com/isangeles/burnsh/data"
 	"github.com/isangeles/burnsh/game"
+	"github.com/isangeles/burnsh/lineedit"
 	"github.com/isangeles/burnsh/log"
 )
 
@@ -64,6 +70,9 @@ const (
 	NewModCmd      = "newmod"
 	SaveGameCmd    = "savegame"
 	LoadGameCmd    = "loadgame"
+	SavesCmd       = "saves"
+	QuicksaveCmd   = "quicksave"
+	QuickloadCmd   = "quickload"
 	ImportCharsCmd = "importchars"
 	MoveCmd        = "move"
 	MoveTarCmd     = "movetar"
@@ -80,20 +89,49 @@ const (
 	EquipCmd       = "equip"
 	InventoryCmd   = "inventory"
 	ChatCmd        = "chat"
+	HelpCmd        = "help"
+	HistoryCmd     = "history"
+	ProfileCmd     = "profile"
+	NetstatCmd     = "netstat"
+	ConnectCmd     = "connect"
+	DisconnectCmd  = "disconnect"
+	ServersCmd     = "servers"
+	PartyCmd       = "party"
 	RepeatInputCmd = "!"
 	InputIndicator = ">"
+	ShellTool      = "burnsh"
 )
 
 var (
 	mod         *flame.Module
 	server      *game.Server
 	activeGame  *game.Game
+	editor      *lineedit.Editor
 	lastCommand string
-	lastUpdate  time.Time
+	batchMode   bool
+	// Stop channel of the game loop for the active game.
+	gameStop  chan struct{}
+	stopMutex sync.Mutex
 )
 
+// On init.
+func init() {
+	burn.AddToolHandler(ShellTool, handleShellTool)
+}
+
 // Main function.
 func main() {
+	// Command line flags.
+	var steps []batchStep
+	batch := flag.Bool("batch", false, "run commands and scripts without user input and exit")
+	save := flag.String("save", "", "name of the save to load in batch mode")
+	simTime := flag.Int64("sim", 0, "time in milliseconds to simulate the game in batch mode")
+	flag.Var(batchFlag{steps: &steps}, "cmd", "shell command to run in batch mode, can be repeated")
+	flag.Var(batchFlag{steps: &steps, script: true}, "script", "name of the Ash script to run in batch mode, can be repeated")
+	flag.StringVar(&recordPath, "record", "", "record game server session in specified file")
+	replay := flag.String("replay", "", "replay game server session recorded in specified file")
+	config.SetFlags(flag.CommandLine)
+	flag.Parse()
 	fmt.Printf("*%s(%s)@%s(%s)*\n", Name, Version,
 		flame.Name, flame.Version)
 	// Load CLI config.
@@ -113,214 +151,251 @@ func main() {
 		log.Err.Printf("Unable to load UI data: %v", err)
 	}
 	// Fire server.
-	if config.Multiplayer() {
-		serv, err := game.NewServer(config.ServerHost, config.ServerPort, config.ServerTLS)
+	if len(*replay) > 0 {
+		err := replaySession(*replay)
+		if err != nil {
+			panic(fmt.Errorf("Unable to replay session: %v", err))
+		}
+	} else if config.Multiplayer() {
+		err := connectServer(config.ServerHost, config.ServerPort, config.ServerTLS)
 		if err != nil {
 			panic(fmt.Errorf("Unable to create game server connection: %v",
 				err))
 		}
-		server = serv
-		server.SetOnResponseFunc(handleResponse)
-		log.Inf.Printf("Connected to the game server at: %s", server.Address())
-	}
-	fmt.Print(InputIndicator)
-	scan := bufio.NewScanner(os.Stdin)
-	for scan.Scan() {
-		input := scan.Text()
-		if strings.HasPrefix(input, CommandPrefix) {
-			cmd := strings.TrimPrefix(input, CommandPrefix)
-			execute(cmd)
-			lastCommand = cmd
-		} else if strings.HasPrefix(input, ScriptPrefix) {
-			input := strings.TrimPrefix(input, ScriptPrefix)
-			scrArgs := strings.Split(input, " ")
-			bgrun := false
-			if strings.HasSuffix(scrArgs[0], RunBGSuffix) {
-				bgrun = true
-				scrArgs[0] = strings.TrimSuffix(scrArgs[0], RunBGSuffix)
-			}
-			executeFile(bgrun, scrArgs[0], scrArgs...)
-		} else if activeGame != nil && activeGame.ActivePlayer() != nil {
-			activeGame.ActivePlayer().AddChatMessage(input)
-		} else {
-			log.Inf.Println(input)
-		}
-		fmt.Print(InputIndicator)
 	}
-	if err := scan.Err(); err != nil {
-		log.Err.Printf("unable to init input scanner : %v\n", err)
-	}
-}
-
-// execute handles specified command or passes it to CI.
-func execute(input string) {
-	switch input {
-	case CloseCmd:
-		err := config.Save()
-		if err != nil {
-			log.Err.Printf("unable to save config: %v", err)
+	// Batch mode.
+	if *batch {
+		batchMode = true
+		opts := batchOptions{
+			save:    *save,
+			steps:   steps,
+			simTime: *simTime,
 		}
-		if server != nil {
-			req := request.Request{Close: time.Now().UnixNano()}
-			err := server.Send(req)
-			if err != nil {
-				log.Err.Printf("Unable to send close request: %v", err)
-			}
-		}
-		os.Exit(0)
-	case LoginCmd:
-		err := loginDialog()
+		fails, err := runBatch(opts)
 		if err != nil {
-			log.Err.Printf("Login error: %v", err)
+			log.Err.Printf("Unable to run batch: %v", err)
+			os.Exit(2)
 		}
-	case NewCharCmd:
-		charData, err := newCharacterDialog(mod)
-		if err != nil {
-			log.Err.Printf("%s\n", err)
-			break
+		if fails > 0 {
+			log.Err.Printf("Batch failed: %d failed commands and scripts", fails)
+			os.Exit(1)
 		}
-		playableChars = append(playableChars, charData)
-	case NewGameCmd:
-		err := newGameDialog()
+		return
+	}
+	// Input.
+	editor = lineedit.New(os.Stdin, os.Stdout)
+	editor.SetCompleteFunc(completeInput)
+	editor.SetHistory(lineedit.NewHistory(config.HistorySize))
+	if _, err := os.Stat(config.HistoryPath()); err == nil {
+		err = editor.History().Load(config.HistoryPath())
 		if err != nil {
-			log.Err.Printf("%s: %v", NewGameCmd, err)
-			break
+			log.Err.Printf("Unable to load input history: %v", err)
 		}
-		go gameLoop(activeGame)
-	case SaveGameCmd:
-		err := saveGameDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", SaveGameCmd, err)
+	}
+	err = migrateCredentials()
+	if err != nil {
+		log.Err.Printf("Unable to move server credentials to the credentials file: %v", err)
+	}
+	for {
+		line, err := editor.ReadLine(InputIndicator)
+		if err == lineedit.ErrInterrupted {
+			continue
 		}
-	case LoadGameCmd:
-		err := loadGameDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", LoadGameCmd, err)
+		if err == io.EOF {
 			break
 		}
-		lastUpdate = time.Now()
-	case ImportCharsCmd:
-		err := importPlayableChars()
-		if err != nil {
-			log.Err.Printf("%s: %v", ImportCharsCmd, err)
-		}
-	case MoveCmd:
-		err := moveDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", MoveCmd, err)
-		}
-	case MoveTarCmd:
-		err := moveTarDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", MoveTarCmd, err)
-		}
-	case LootTargetCmd:
-		err := lootDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", LootTargetCmd, err)
-		}
-	case TalkTargetCmd:
-		err := talkDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", TalkTargetCmd, err)
-		}
-	case FindTargetCmd:
-		err := targetDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", FindTargetCmd, err)
-		}
-	case TargetInfoCmd:
-		err := targetInfoDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", TargetInfoCmd, err)
-		}
-	case AreaInfoCmd:
-		err := areaInfoDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", AreaInfoCmd, err)
-		}
-	case QuestsCmd:
-		err := questsDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", QuestsCmd, err)
-		}
-	case UseSkillCmd:
-		err := useSkillDialog()
 		if err != nil {
-			log.Err.Printf("%s: %v", UseSkillCmd, err)
+			log.Err.Printf("unable to read input: %v\n", err)
+			break
 		}
-	case CraftingCmd:
-		err := craftingDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", CraftingCmd, err)
+		if strings.HasPrefix(line, CommandPrefix+RepeatInputCmd) {
+			line, err = expandHistory(line)
+			if err != nil {
+				log.Err.Printf("%v", err)
+				continue
+			}
+			fmt.Printf("%s\n", line)
 		}
-	case TradeTargetCmd:
-		err := tradeDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", TradeTargetCmd, err)
+		if keepInHistory(line) {
+			editor.History().Add(line)
 		}
-	case TrainTargetCmd:
-		err := trainDialog()
+		err = handleInput(line)
 		if err != nil {
-			log.Err.Printf("%s: %v", TrainTargetCmd, err)
+			log.Err.Printf("%v", err)
 		}
-	case EquipCmd:
-		err := equipDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", EquipCmd, err)
+	}
+	saveHistory()
+}
+
+// keepInHistory checks if specified input line can be kept
+// in the input history.
+// Login commands with credentials are not kept, so passwords
+// are never written to the history file or saves.
+func keepInHistory(line string) bool {
+	if !strings.HasPrefix(line, CommandPrefix) {
+		return true
+	}
+	args := strings.Fields(strings.TrimPrefix(line, CommandPrefix))
+	if len(args) < 2 || args[1] == loginForgetArg {
+		return true
+	}
+	cmd := command.Find(args[0])
+	return cmd == nil || cmd.Name != LoginCmd
+}
+
+// handleInput handles specified input line.
+// Returns error if command or script from the input
+// failed.
+func handleInput(input string) error {
+	if strings.HasPrefix(input, CommandPrefix) {
+		cmd := strings.TrimPrefix(input, CommandPrefix)
+		lastCommand = cmd
+		return execute(cmd)
+	} else if strings.HasPrefix(input, ScriptPrefix) {
+		input := strings.TrimPrefix(input, ScriptPrefix)
+		scrArgs := strings.Split(input, " ")
+		bgrun := false
+		if strings.HasSuffix(scrArgs[0], RunBGSuffix) {
+			bgrun = true
+			scrArgs[0] = strings.TrimSuffix(scrArgs[0], RunBGSuffix)
 		}
-	case InventoryCmd:
-		err := inventoryDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", InventoryCmd, err)
+		return executeFile(bgrun, scrArgs[0], scrArgs...)
+	} else if activeGame != nil && actingPlayer() != nil {
+		actingPlayer().AddChatMessage(input)
+	} else {
+		log.Inf.Println(input)
+	}
+	return nil
+}
+
+// expandHistory replaces specified history recall input with
+// the line from the input history.
+// Supported forms: '$!'(last line), '$!n'(line number n), and
+// '$!prefix'(last command starting with prefix).
+func expandHistory(line string) (string, error) {
+	recall := strings.TrimPrefix(line, CommandPrefix+RepeatInputCmd)
+	if len(recall) < 1 {
+		histLine, ok := editor.History().Line(-1)
+		if !ok {
+			return "", fmt.Errorf("%s", lang.Text("history_empty_err"))
 		}
-	case ChatCmd:
-		err := chatDialog()
-		if err != nil {
-			log.Err.Printf("%s: %v", ChatCmd, err)
+		return histLine, nil
+	}
+	if n, err := strconv.Atoi(recall); err == nil {
+		histLine, ok := editor.History().Line(n)
+		if !ok {
+			return "", fmt.Errorf("%s: %s", lang.Text("history_not_found_err"), recall)
 		}
-	case RepeatInputCmd:
-		execute(lastCommand)
-	default: // pass command to CI
-		exp, err := syntax.NewSTDExpression(input)
-		if err != nil {
-			log.Err.Printf("command build error: %v", err)
-			break
+		return histLine, nil
+	}
+	histLine, ok := editor.History().Find(CommandPrefix + recall)
+	if !ok {
+		return "", fmt.Errorf("%s: %s", lang.Text("history_not_found_err"), recall)
+	}
+	return histLine, nil
+}
+
+// saveHistory saves input history in the history file.
+func saveHistory() {
+	if editor == nil {
+		return
+	}
+	err := editor.History().Save(config.HistoryPath())
+	if err != nil {
+		log.Err.Printf("Unable to save input history: %v", err)
+	}
+}
+
+// execute handles specified command or passes it to CI.
+// Command name can be followed by command arguments
+// separated by whitespaces.
+// Returns error if command failed or CI returned
+// non-zero result.
+func execute(input string) error {
+	cmdArgs := strings.Fields(input)
+	if len(cmdArgs) < 1 {
+		return nil
+	}
+	err := command.Run(cmdArgs[0], cmdArgs[1:]...)
+	if err == command.ErrUnknownCommand {
+		res := executeCI(input)
+		if res == 0 {
+			return nil
 		}
-		res, out := burn.HandleExpression(exp)
-		log.Inf.Printf("burn[%d]: %s\n", res, out)
-		if server != nil {
-			req := request.Request{Command: []string{exp.String()}}
-			server.Send(req)
+		names := command.Suggest(cmdArgs[0])
+		if len(names) > 0 {
+			fmt.Printf("%s: %s\n", lang.Text("help_suggest"),
+				strings.Join(names, ", "))
 		}
+		return fmt.Errorf("%s: CI result: %d", cmdArgs[0], res)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %v", cmdArgs[0], err)
+	}
+	return nil
+}
+
+// executeCI passes specified input to CI.
+// Returns CI result code.
+func executeCI(input string) int {
+	exp, err := syntax.NewSTDExpression(input)
+	if err != nil {
+		log.Err.Printf("command build error: %v", err)
+		return -1
+	}
+	res, out := burn.HandleExpression(exp)
+	log.Inf.Printf("burn[%d]: %s\n", res, out)
+	if server != nil {
+		req := request.Request{Command: []string{exp.String()}}
+		server.Send(req)
+	}
+	return res
+}
+
+// handleShellTool handles Burn Shell tool for CI.
+// Runs build-in command with name from the first option
+// argument and CI command arguments as command arguments,
+// e.g. 'burnsh -o move -a 120 40'.
+func handleShellTool(cmd burn.Command) (int, string) {
+	if len(cmd.OptionArgs()) < 1 {
+		return 2, fmt.Sprintf("%s: no option args", ShellTool)
+	}
+	err := command.Run(cmd.OptionArgs()[0], cmd.Args()...)
+	if err != nil {
+		return 3, fmt.Sprintf("%s: %s: %v", ShellTool, cmd.OptionArgs()[0], err)
 	}
+	return 0, ""
 }
 
 // executeFile executes script from data/scripts dir.
-func executeFile(bgrun bool, fileName string, args ...string) {
+// Returns error if script failed, scripts executed in
+// background are reported only in the log.
+func executeFile(bgrun bool, fileName string, args ...string) error {
 	path := fmt.Sprintf("%s/%s.ash", config.ScriptsPath(),
 		fileName)
 	file, err := os.Open(path)
 	if err != nil {
-		log.Err.Printf("unable to open file: %v", err)
-		return
+		return fmt.Errorf("unable to open file: %v", err)
 	}
+	defer file.Close()
 	text, err := ioutil.ReadAll(file)
 	if err != nil {
-		log.Err.Printf("unable to read file: %v", err)
-		return
+		return fmt.Errorf("unable to read file: %v", err)
 	}
 	scriptName := filepath.Base(path)
 	scr, err := ash.NewScript(scriptName, fmt.Sprintf("%s", text), args...)
 	if err != nil {
-		log.Err.Printf("unable to parse script: %v", err)
-		return
+		return fmt.Errorf("unable to parse script: %v", err)
 	}
 	if bgrun {
 		go runScript(scr)
-		return
+		return nil
 	}
-	runScript(scr)
+	err = ash.Run(scr)
+	if err != nil {
+		return fmt.Errorf("unable to run script: %v", err)
+	}
+	return nil
 }
 
 // runScript runs sprecified Ash script.
@@ -332,16 +407,85 @@ func runScript(s *ash.Script) {
 	}
 }
 
+// startGame sets specified game as active game and
+// starts game loop for it.
+func startGame(g *game.Game) {
+	setActiveGame(g)
+	startGameLoop(g)
+}
+
+// startGameLoop starts game loop for specified game.
+// In batch mode the loop is not started, since the game
+// is updated only by the batch simulation.
+func startGameLoop(g *game.Game) {
+	if batchMode {
+		return
+	}
+	stop := make(chan struct{})
+	stopMutex.Lock()
+	gameStop = stop
+	stopMutex.Unlock()
+	go gameLoop(g, stop)
+}
+
+// stopActiveGame stops game loop for the active game
+// and removes the active game.
+func stopActiveGame() {
+	stopGameLoop()
+	activeGame = nil
+}
+
+// stopGameLoop stops game loop for the active game,
+// if the loop was started.
+func stopGameLoop() {
+	stopMutex.Lock()
+	defer stopMutex.Unlock()
+	if gameStop == nil {
+		return
+	}
+	close(gameStop)
+	gameStop = nil
+}
+
+// setActiveGame sets specified game as active game.
+// Party formation for the game is set from the config.
+// Players arrivals are reported to the user.
+func setActiveGame(g *game.Game) {
+	mod = g.Module
+	burn.Module = g.Module
+	f, err := game.ParseFormation(config.Formation)
+	if err != nil {
+		log.Err.Printf("Invalid formation config: %v", err)
+	}
+	g.SetFormation(f, config.FormationSpacing)
+	g.SetOnArrivalFunc(handleArrival)
+	stopGameLoop()
+	activeGame = g
+}
+
 // gameLoop handles game updating.
-func gameLoop(g *game.Game) {
-	lastUpdate = time.Now()
+// Loop ends when specified stop channel is closed,
+// i.e. when specified game is no longer the active game.
+func gameLoop(g *game.Game, stop <-chan struct{}) {
+	lastUpdate := time.Now()
+	lastAutosave := time.Now()
 	for {
 		dtNano := time.Since(lastUpdate).Nanoseconds()
 		delta := dtNano / int64(time.Millisecond) // delta to milliseconds
 		g.Update(delta)
 		lastUpdate = time.Now()
+		// Autosave.
+		autosaveInterval := time.Duration(config.AutosaveInterval) * time.Minute
+		if autosaveInterval > 0 && time.Since(lastAutosave) >= autosaveInterval {
+			autosave(g)
+			lastAutosave = time.Now()
+		}
 		// Wait for 16 millis.
-		time.Sleep(time.Duration(16) * time.Millisecond)
+		select {
+		case <-stop:
+			return
+		case <-time.After(time.Duration(16) * time.Millisecond):
+		}
 	}
 }
 
@@ -0,0 +1,45 @@
+/*
+ * cli_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"testing"
+)
+
+// TestKeepInHistory tests keeping login commands with
+// credentials out of the input history.
+func TestKeepInHistory(t *testing.T) {
+	lines := map[string]bool{
+		"$login user_test pass_test": false,
+		"$login -f":                  true,
+		"$login":                     true,
+		"$move 10 20":                true,
+		"login user_test pass_test":  true,
+	}
+	for l, keep := range lines {
+		if keepInHistory(l) != keep {
+			t.Errorf("History line keep invalid: %s: %v != %v", l, keepInHistory(l), keep)
+		}
+	}
+}
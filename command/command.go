@@ -0,0 +1,237 @@
+/*
+ * command.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+// Package with registry for CLI build-in commands.
+// Commands registered in the default registry are available
+// as build-in commands of the shell, so other packages can add
+// new commands by calling Register, e.g. in the package init function.
+package command
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Struct for CLI command.
+type Command struct {
+	// Command name.
+	Name string
+	// Alternative names for the command.
+	Aliases []string
+	// Command arguments specification, e.g. '[X Y]'.
+	Args string
+	// ID of the translation for command help text.
+	Help string
+	// Function that handles command with specified arguments.
+	Run func(args ...string) error
+	// Optional function that returns candidates for the next
+	// command argument, takes already specified arguments.
+	Complete func(args ...string) []string
+}
+
+// Struct for commands registry.
+type Registry struct {
+	mutex    sync.RWMutex
+	commands map[string]*Command
+	aliases  map[string]string
+}
+
+// Maximal edit distance between unknown command name and
+// command name suggested instead.
+const suggestDistance = 2
+
+var (
+	ErrUnknownCommand = errors.New("unknown command")
+
+	defaultRegistry = NewRegistry()
+)
+
+// NewRegistry creates new commands registry.
+func NewRegistry() *Registry {
+	r := Registry{
+		commands: make(map[string]*Command),
+		aliases:  make(map[string]string),
+	}
+	return &r
+}
+
+// Register adds specified command to the registry.
+// Returns error if command is invalid or its name or
+// one of its aliases is already registered.
+func (r *Registry) Register(cmd Command) error {
+	if len(cmd.Name) < 1 {
+		return fmt.Errorf("no command name")
+	}
+	if cmd.Run == nil {
+		return fmt.Errorf("%s: no command handler", cmd.Name)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, n := range append([]string{cmd.Name}, cmd.Aliases...) {
+		if r.commands[n] != nil || len(r.aliases[n]) > 0 {
+			return fmt.Errorf("%s: name already registered: %s",
+				cmd.Name, n)
+		}
+	}
+	r.commands[cmd.Name] = &cmd
+	for _, a := range cmd.Aliases {
+		r.aliases[a] = cmd.Name
+	}
+	return nil
+}
+
+// Command returns command with specified name or alias,
+// or nil if there is no such command in the registry.
+func (r *Registry) Command(name string) *Command {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if alias, ok := r.aliases[name]; ok {
+		name = alias
+	}
+	return r.commands[name]
+}
+
+// Commands returns all registered commands sorted
+// by the command name.
+func (r *Registry) Commands() []*Command {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	cmds := make([]*Command, 0, len(r.commands))
+	for _, c := range r.commands {
+		cmds = append(cmds, c)
+	}
+	sort.Slice(cmds, func(i, j int) bool {
+		return cmds[i].Name < cmds[j].Name
+	})
+	return cmds
+}
+
+// Run runs command with specified name or alias with
+// specified arguments.
+// Returns ErrUnknownCommand if there is no such command
+// in the registry.
+func (r *Registry) Run(name string, args ...string) error {
+	cmd := r.Command(name)
+	if cmd == nil {
+		return ErrUnknownCommand
+	}
+	return cmd.Run(args...)
+}
+
+// Names returns names and aliases of all registered commands.
+func (r *Registry) Names() []string {
+	names := make([]string, 0)
+	for _, c := range r.Commands() {
+		names = append(names, c.Name)
+		names = append(names, c.Aliases...)
+	}
+	return names
+}
+
+// Suggest returns names of registered commands similar
+// to specified name.
+func (r *Registry) Suggest(name string) (names []string) {
+	for _, c := range r.Commands() {
+		for _, n := range append([]string{c.Name}, c.Aliases...) {
+			if strings.HasPrefix(n, name) ||
+				editDistance(n, name) <= suggestDistance {
+				names = append(names, c.Name)
+				break
+			}
+		}
+	}
+	return
+}
+
+// Usage returns command usage text.
+func (c *Command) Usage() string {
+	if len(c.Args) < 1 {
+		return c.Name
+	}
+	return fmt.Sprintf("%s %s", c.Name, c.Args)
+}
+
+// Register adds specified command to the default registry.
+func Register(cmd Command) error {
+	return defaultRegistry.Register(cmd)
+}
+
+// Find returns command with specified name or alias from
+// the default registry.
+func Find(name string) *Command {
+	return defaultRegistry.Command(name)
+}
+
+// Commands returns all commands from the default registry.
+func Commands() []*Command {
+	return defaultRegistry.Commands()
+}
+
+// Names returns names and aliases of all commands from
+// the default registry.
+func Names() []string {
+	return defaultRegistry.Names()
+}
+
+// Run runs command with specified name from the default registry.
+func Run(name string, args ...string) error {
+	return defaultRegistry.Run(name, args...)
+}
+
+// Suggest returns names of commands from the default registry
+// similar to specified name.
+func Suggest(name string) []string {
+	return defaultRegistry.Suggest(name)
+}
+
+// editDistance returns Levenshtein distance between
+// specified texts.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
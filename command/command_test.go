@@ -0,0 +1,95 @@
+/*
+ * command_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package command
+
+import (
+	"testing"
+)
+
+// TestRegistryRun tests running registered commands by
+// name and alias.
+func TestRegistryRun(t *testing.T) {
+	reg := NewRegistry()
+	runArgs := make([]string, 0)
+	cmd := Command{
+		Name:    "move",
+		Aliases: []string{"mv"},
+		Run: func(args ...string) error {
+			runArgs = args
+			return nil
+		},
+	}
+	err := reg.Register(cmd)
+	if err != nil {
+		t.Fatalf("Unable to register command: %v", err)
+	}
+	err = reg.Run("mv", "10", "20")
+	if err != nil {
+		t.Fatalf("Unable to run command: %v", err)
+	}
+	if len(runArgs) != 2 || runArgs[0] != "10" || runArgs[1] != "20" {
+		t.Errorf("Command arguments invalid: %v", runArgs)
+	}
+	err = reg.Run("moev")
+	if err != ErrUnknownCommand {
+		t.Errorf("Unknown command error invalid: %v", err)
+	}
+}
+
+// TestRegistryRegisterDuplicate tests registering command
+// with already registered name.
+func TestRegistryRegisterDuplicate(t *testing.T) {
+	reg := NewRegistry()
+	run := func(args ...string) error { return nil }
+	err := reg.Register(Command{Name: "target", Aliases: []string{"tar"}, Run: run})
+	if err != nil {
+		t.Fatalf("Unable to register command: %v", err)
+	}
+	err = reg.Register(Command{Name: "tar", Run: run})
+	if err == nil {
+		t.Errorf("Command with registered alias as name was registered")
+	}
+	err = reg.Register(Command{Name: "tarinfo", Aliases: []string{"target"}, Run: run})
+	if err == nil {
+		t.Errorf("Command with registered name as alias was registered")
+	}
+}
+
+// TestRegistrySuggest tests suggesting commands for
+// unknown command names.
+func TestRegistrySuggest(t *testing.T) {
+	reg := NewRegistry()
+	run := func(args ...string) error { return nil }
+	reg.Register(Command{Name: "inventory", Run: run})
+	reg.Register(Command{Name: "move", Run: run})
+	reg.Register(Command{Name: "movetar", Run: run})
+	names := reg.Suggest("mvoe")
+	if len(names) != 1 || names[0] != "move" {
+		t.Errorf("Suggested names invalid: %v != [move]", names)
+	}
+	names = reg.Suggest("inv")
+	if len(names) != 1 || names[0] != "inventory" {
+		t.Errorf("Suggested names invalid: %v != [inventory]", names)
+	}
+}
@@ -0,0 +1,237 @@
+/*
+ * commands.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/isangeles/flame/data/res/lang"
+
+	"github.com/isangeles/fire/request"
+
+	"github.com/isangeles/burnsh/command"
+	"github.com/isangeles/burnsh/config"
+	"github.com/isangeles/burnsh/log"
+)
+
+// On init.
+func init() {
+	cmds := []command.Command{
+		{Name: CloseCmd, Aliases: []string{"exit", "quit"}, Help: "help_close",
+			Run: closeCommand},
+		{Name: HelpCmd, Args: "[command]", Help: "help_help",
+			Run: helpCommand, Complete: completeCommands},
+		{Name: HistoryCmd, Help: "help_history",
+			Run: historyCommand},
+		{Name: RepeatInputCmd, Args: "[n|prefix]", Help: "help_repeat",
+			Run: repeatCommand},
+		{Name: ProfileCmd, Args: "[list|profile name]", Help: "help_profile",
+			Run: profileCommand, Complete: completeProfiles},
+		{Name: LoginCmd, Args: "[login [password]|-f]", Help: "help_login",
+			Run: loginDialog},
+		{Name: NewCharCmd, Args: "[name race gender [str con dex wis int]]",
+			Help: "help_newchar", Run: newCharCommand},
+		{Name: NewGameCmd, Args: "[character ID]", Help: "help_newgame",
+			Run: newGameCommand, Complete: completePlayableChars},
+		{Name: NewModCmd, Args: "[module ID]", Help: "help_newmod",
+			Run: newModDialog},
+		{Name: SaveGameCmd, Args: "[save name]", Help: "help_savegame",
+			Run: saveGameDialog},
+		{Name: LoadGameCmd, Args: "[save name]", Help: "help_loadgame",
+			Run: loadGameCommand, Complete: completeSaves},
+		{Name: SavesCmd, Args: "[list|info save|rename save name|copy save name|delete save]",
+			Help: "help_saves", Run: savesCommand, Complete: completeSavesCmd},
+		{Name: QuicksaveCmd, Help: "help_quicksave",
+			Run: quicksaveCommand},
+		{Name: QuickloadCmd, Help: "help_quickload",
+			Run: quickloadCommand},
+		{Name: ImportCharsCmd, Help: "help_importchars",
+			Run: noArgs(importPlayableChars)},
+		{Name: MoveCmd, Args: "[X Y|point|ID[#serial] ...]", Help: "help_move",
+			Run: moveDialog, Complete: completeDestinations},
+		{Name: MoveTarCmd, Help: "help_movetar",
+			Run: noArgs(moveTarDialog)},
+		{Name: LootTargetCmd, Help: "help_loot",
+			Run: noArgs(lootDialog)},
+		{Name: TalkTargetCmd, Args: "[answer ID ...]", Help: "help_talk",
+			Run: talkDialog},
+		{Name: FindTargetCmd, Aliases: []string{"tar"}, Args: "[ID[#serial]]",
+			Help: "help_target", Run: targetDialog, Complete: completeNearObjects},
+		{Name: TargetInfoCmd, Help: "help_tarinfo",
+			Run: noArgs(targetInfoDialog)},
+		{Name: AreaInfoCmd, Help: "help_areainfo",
+			Run: noArgs(areaInfoDialog)},
+		{Name: QuestsCmd, Help: "help_quests",
+			Run: noArgs(questsDialog)},
+		{Name: UseSkillCmd, Args: "[skill ID]", Help: "help_useskill",
+			Run: useSkillDialog, Complete: completeSkills},
+		{Name: CraftingCmd, Args: "[recipe ID]", Help: "help_crafting",
+			Run: craftingDialog, Complete: completeRecipes},
+		{Name: TradeTargetCmd, Args: "[-b item ID[#serial] ...] [-s item ID[#serial] ...]",
+			Help: "help_trade", Run: tradeDialog, Complete: completeTrade},
+		{Name: TrainTargetCmd, Args: "[training ID]", Help: "help_train",
+			Run: trainDialog},
+		{Name: EquipCmd, Args: "[item ID[#serial]]", Help: "help_equip",
+			Run: equipDialog, Complete: completeInventory},
+		{Name: InventoryCmd, Aliases: []string{"inv"}, Help: "help_inventory",
+			Run: noArgs(inventoryDialog)},
+		{Name: PartyCmd, Args: "[list|add character ID|switch ID[#serial]|do ID[#serial] command ...|formation [name [spacing]]]",
+			Help: "help_party", Run: partyCommand, Complete: completePartyCmd},
+		{Name: ChatCmd, Args: "[message]", Help: "help_chat",
+			Run: chatDialog},
+		{Name: NetstatCmd, Help: "help_netstat",
+			Run: noArgs(netstatDialog)},
+		{Name: ConnectCmd, Args: "[server name|host port]", Help: "help_connect",
+			Run: connectCommand, Complete: completeBookmarks},
+		{Name: DisconnectCmd, Help: "help_disconnect",
+			Run: disconnectCommand},
+		{Name: ServersCmd, Args: "[list|add name host port [login] [-tls]|remove name]",
+			Help: "help_servers", Run: serversCommand, Complete: completeServersCmd},
+	}
+	for _, c := range cmds {
+		err := command.Register(c)
+		if err != nil {
+			panic(fmt.Errorf("Unable to register build-in command: %v", err))
+		}
+	}
+}
+
+// noArgs wraps specified function without arguments into
+// command handler function.
+func noArgs(f func() error) func(args ...string) error {
+	return func(args ...string) error {
+		return f()
+	}
+}
+
+// closeCommand handles close command.
+func closeCommand(args ...string) error {
+	err := config.Save()
+	if err != nil {
+		log.Err.Printf("unable to save config: %v", err)
+	}
+	saveHistory()
+	if server != nil {
+		req := request.Request{Close: time.Now().UnixNano()}
+		err := server.Send(req)
+		if err != nil {
+			log.Err.Printf("Unable to send close request: %v", err)
+		}
+	}
+	os.Exit(0)
+	return nil
+}
+
+// helpCommand handles help command.
+// Prints all commands or help for command with name
+// specified as an argument.
+func helpCommand(args ...string) error {
+	if len(args) > 0 {
+		cmd := command.Find(args[0])
+		if cmd == nil {
+			return fmt.Errorf("%s: %s", lang.Text("help_unknown_cmd"), args[0])
+		}
+		fmt.Printf("%s%s\n", CommandPrefix, cmd.Usage())
+		if len(cmd.Aliases) > 0 {
+			fmt.Printf("%s: %s\n", lang.Text("help_aliases"),
+				strings.Join(cmd.Aliases, ", "))
+		}
+		fmt.Printf("%s\n", lang.Text(cmd.Help))
+		return nil
+	}
+	fmt.Printf("%s:\n", lang.Text("help_commands"))
+	for _, c := range command.Commands() {
+		fmt.Printf("%s%s\t%s\n", CommandPrefix, c.Usage(), lang.Text(c.Help))
+	}
+	return nil
+}
+
+// repeatCommand handles repeat input command.
+// Repeats last command or line from the input history
+// specified by number or prefix.
+func repeatCommand(args ...string) error {
+	if editor == nil {
+		return execute(lastCommand)
+	}
+	line, err := expandHistory(CommandPrefix + RepeatInputCmd + strings.Join(args, ""))
+	if err != nil {
+		return err
+	}
+	return handleInput(line)
+}
+
+// historyCommand handles history command.
+// Prints numbered lines from the input history.
+func historyCommand(args ...string) error {
+	if editor == nil {
+		return nil
+	}
+	for i, l := range editor.History().Lines() {
+		fmt.Printf("%d\t%s\n", i+1, l)
+	}
+	return nil
+}
+
+// newCharCommand handles new character command.
+func newCharCommand(args ...string) error {
+	charData, err := newCharacterDialog(mod, args...)
+	if err != nil {
+		return err
+	}
+	playableChars = append(playableChars, charData)
+	return nil
+}
+
+// newGameCommand handles new game command.
+func newGameCommand(args ...string) error {
+	err := newGameDialog(args...)
+	if err != nil {
+		return err
+	}
+	startGameLoop(activeGame)
+	return nil
+}
+
+// loadGameCommand handles load game command.
+func loadGameCommand(args ...string) error {
+	return loadGameDialog(args...)
+}
+
+// quicksaveCommand handles quicksave command.
+// Saves current game in the quicksave slot.
+func quicksaveCommand(args ...string) error {
+	if activeGame == nil {
+		return fmt.Errorf("no game started")
+	}
+	return saveGameAs(activeGame, QuicksaveName)
+}
+
+// quickloadCommand handles quickload command.
+// Loads game from the quicksave slot.
+func quickloadCommand(args ...string) error {
+	return loadGameCommand(QuicksaveName)
+}
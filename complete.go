@@ -0,0 +1,317 @@
+/*
+ * complete.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/isangeles/flame/character"
+	flamedata "github.com/isangeles/flame/data"
+	"github.com/isangeles/flame/effect"
+
+	"github.com/isangeles/burn"
+
+	"github.com/isangeles/burnsh/command"
+	"github.com/isangeles/burnsh/config"
+	"github.com/isangeles/burnsh/game"
+)
+
+// completeInput returns completion candidates for the last
+// word of specified input text.
+// Completes command names and command arguments.
+func completeInput(head string) []string {
+	if !strings.HasPrefix(head, CommandPrefix) {
+		return nil
+	}
+	words := strings.Fields(strings.TrimPrefix(head, CommandPrefix))
+	word := ""
+	if len(words) > 0 && !strings.HasSuffix(head, " ") {
+		word = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	if len(words) < 1 {
+		candidates := make([]string, 0)
+		for _, n := range filterPrefix(command.Names(), word) {
+			candidates = append(candidates, CommandPrefix+n)
+		}
+		return candidates
+	}
+	cmd := command.Find(words[0])
+	if cmd == nil || cmd.Complete == nil {
+		return nil
+	}
+	return filterPrefix(cmd.Complete(words[1:]...), word)
+}
+
+// filterPrefix returns all specified texts that start
+// with specified prefix.
+func filterPrefix(texts []string, prefix string) (filtered []string) {
+	for _, t := range texts {
+		if strings.HasPrefix(t, prefix) {
+			filtered = append(filtered, t)
+		}
+	}
+	return
+}
+
+// idSerial returns command argument for game object with
+// specified ID and serial value.
+func idSerial(id, serial string) string {
+	if len(serial) < 1 {
+		return id
+	}
+	return fmt.Sprintf("%s%s%s", id, burn.IDSerialSep, serial)
+}
+
+// completeCommands returns names of all build-in commands.
+func completeCommands(args ...string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	return command.Names()
+}
+
+// completeNearObjects returns IDs of objects near
+// the active player.
+func completeNearObjects(args ...string) (ids []string) {
+	if len(args) > 0 || activeGame == nil || actingPlayer() == nil {
+		return
+	}
+	area := activeGame.Chapter().ObjectArea(actingPlayer())
+	if area == nil {
+		return
+	}
+	pcX, pcY := actingPlayer().Position()
+	for _, t := range area.NearObjects(pcX, pcY, actingPlayer().SightRange()) {
+		ids = append(ids, idSerial(t.ID(), t.Serial()))
+	}
+	return
+}
+
+// completeDestinations returns names of module points and IDs
+// of objects from the area of the active player.
+func completeDestinations(args ...string) (names []string) {
+	if activeGame == nil || actingPlayer() == nil {
+		return
+	}
+	area := activeGame.Chapter().ObjectArea(actingPlayer())
+	if area == nil {
+		return
+	}
+	if mod != nil {
+		points, _ := modulePoints(filepath.Join(mod.Conf().Path, ModulePointsPath))
+		for _, p := range points {
+			if p.Area == area.ID() {
+				names = append(names, p.Name)
+			}
+		}
+	}
+	for _, ob := range area.Objects() {
+		if tar, ok := ob.(effect.Target); ok {
+			names = append(names, idSerial(tar.ID(), tar.Serial()))
+		}
+	}
+	return
+}
+
+// completeInventory returns IDs of items from the active
+// player inventory.
+func completeInventory(args ...string) (ids []string) {
+	if len(args) > 0 || activeGame == nil || actingPlayer() == nil {
+		return
+	}
+	for _, it := range actingPlayer().Inventory().Items() {
+		ids = append(ids, idSerial(it.ID(), it.Serial()))
+	}
+	return
+}
+
+// completeSkills returns IDs of the active player skills.
+func completeSkills(args ...string) (ids []string) {
+	if len(args) > 0 || activeGame == nil || actingPlayer() == nil {
+		return
+	}
+	for _, s := range actingPlayer().Skills() {
+		ids = append(ids, s.ID())
+	}
+	return
+}
+
+// completeRecipes returns IDs of the active player recipes.
+func completeRecipes(args ...string) (ids []string) {
+	if len(args) > 0 || activeGame == nil || actingPlayer() == nil {
+		return
+	}
+	for _, r := range actingPlayer().Crafting().Recipes() {
+		ids = append(ids, r.ID())
+	}
+	return
+}
+
+// completeTrade returns trade flags and IDs of items to buy
+// or sell, depending on the last trade flag.
+func completeTrade(args ...string) []string {
+	ids := []string{tradeBuyArg, tradeSellArg}
+	if activeGame == nil || actingPlayer() == nil {
+		return ids
+	}
+	flag := ""
+	for _, a := range args {
+		if a == tradeBuyArg || a == tradeSellArg {
+			flag = a
+		}
+	}
+	switch flag {
+	case tradeSellArg:
+		ids = append(ids, completeInventory()...)
+	case tradeBuyArg:
+		tars := actingPlayer().Targets()
+		if len(tars) < 1 {
+			break
+		}
+		tarChar, ok := tars[0].(*character.Character)
+		if !ok {
+			break
+		}
+		for _, it := range tarChar.Inventory().Items() {
+			if it.Trade {
+				ids = append(ids, idSerial(it.ID(), it.Serial()))
+			}
+		}
+	}
+	return ids
+}
+
+// completePlayableChars returns IDs of playable characters.
+func completePlayableChars(args ...string) (ids []string) {
+	if len(args) > 0 {
+		return
+	}
+	for _, c := range playableChars {
+		ids = append(ids, c.ID)
+	}
+	return
+}
+
+// completeSaves returns names of saved games.
+func completeSaves(args ...string) (names []string) {
+	if len(args) > 0 || mod == nil {
+		return
+	}
+	path := filepath.Join(mod.Conf().Path, ModuleSavesPath)
+	saves, err := flamedata.DirFilesNames(path, fmt.Sprintf(".*%s", SaveExt))
+	if err != nil {
+		return
+	}
+	for _, s := range saves {
+		names = append(names, strings.TrimSuffix(s, SaveExt))
+	}
+	return
+}
+
+// completeSavesCmd returns completions for saves command
+// arguments.
+func completeSavesCmd(args ...string) []string {
+	switch {
+	case len(args) < 1:
+		return []string{savesListArg, savesInfoArg, savesRenameArg,
+			savesCopyArg, savesDeleteArg}
+	case len(args) == 1 && args[0] != savesListArg:
+		return completeSaves()
+	default:
+		return nil
+	}
+}
+
+// completeBookmarks returns names of bookmarked servers.
+func completeBookmarks(args ...string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	bookmarks, err := config.Bookmarks()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		names = append(names, b.Name)
+	}
+	return names
+}
+
+// completeServersCmd returns completions for servers command
+// arguments.
+func completeServersCmd(args ...string) []string {
+	switch {
+	case len(args) < 1:
+		return []string{serversListArg, serversAddArg, serversRemoveArg}
+	case len(args) == 1 && args[0] == serversRemoveArg:
+		return completeBookmarks()
+	default:
+		return nil
+	}
+}
+
+// completePlayers returns IDs of player characters from
+// the active game.
+func completePlayers(args ...string) (ids []string) {
+	if len(args) > 0 || activeGame == nil {
+		return
+	}
+	for _, pc := range activeGame.Players() {
+		ids = append(ids, idSerial(pc.ID(), pc.Serial()))
+	}
+	return
+}
+
+// completePartyCmd returns completions for party command
+// arguments.
+// Arguments of the command run as party member are completed
+// by the command completion function.
+func completePartyCmd(args ...string) []string {
+	switch {
+	case len(args) < 1:
+		return []string{partyListArg, partyAddArg, partySwitchArg, partyDoArg,
+			partyFormArg}
+	case len(args) == 1 && args[0] == partyAddArg:
+		return completePlayableChars()
+	case len(args) == 1 && (args[0] == partySwitchArg || args[0] == partyDoArg):
+		return completePlayers()
+	case len(args) == 1 && args[0] == partyFormArg:
+		return []string{string(game.FormationNone), string(game.FormationLine),
+			string(game.FormationColumn), string(game.FormationWedge)}
+	case len(args) == 2 && args[0] == partyDoArg:
+		return completeCommands()
+	case args[0] == partyDoArg:
+		cmd := command.Find(args[2])
+		if cmd == nil || cmd.Complete == nil {
+			return nil
+		}
+		return cmd.Complete(args[3:]...)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * bookmarks.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const BookmarksFileName = ".burnsh_servers"
+
+// Struct for bookmarked game server.
+// Passwords for bookmarked servers are stored in the
+// credentials file.
+type Bookmark struct {
+	Name  string
+	Host  string
+	Port  string
+	TLS   bool
+	Login string
+}
+
+// Bookmarks loads bookmarked servers from the bookmarks file.
+// Each line of the file contains server name, host, port,
+// TLS flag and login, separated by ';'.
+// Returns no bookmarks if the bookmarks file doesn't exist.
+func Bookmarks() ([]Bookmark, error) {
+	data, err := os.ReadFile(BookmarksPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read bookmarks file: %v", err)
+	}
+	bookmarks := make([]Bookmark, 0)
+	for i, l := range strings.Split(string(data), "\n") {
+		l = strings.TrimSpace(l)
+		if len(l) < 1 || strings.HasPrefix(l, CommentPrefix) {
+			continue
+		}
+		values := strings.Split(l, ";")
+		if len(values) < 3 {
+			return nil, fmt.Errorf("invalid bookmark at line %d: %s", i+1, l)
+		}
+		b := Bookmark{Name: values[0], Host: values[1], Port: values[2]}
+		if len(values) > 3 {
+			b.TLS = values[3] == "true"
+		}
+		if len(values) > 4 {
+			b.Login = values[4]
+		}
+		bookmarks = append(bookmarks, b)
+	}
+	return bookmarks, nil
+}
+
+// SaveBookmarks saves specified bookmarks in the bookmarks file.
+func SaveBookmarks(bookmarks []Bookmark) error {
+	lines := make([]string, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		lines = append(lines, fmt.Sprintf("%s;%s;%s;%v;%s", b.Name, b.Host,
+			b.Port, b.TLS, b.Login))
+	}
+	err := os.MkdirAll(filepath.Dir(BookmarksPath()), 0755)
+	if err != nil {
+		return fmt.Errorf("unable to create bookmarks directory: %v", err)
+	}
+	err = os.WriteFile(BookmarksPath(), []byte(strings.Join(lines, "\n")+"\n"), 0644)
+	if err != nil {
+		return fmt.Errorf("unable to write bookmarks file: %v", err)
+	}
+	return nil
+}
+
+// FindBookmark returns bookmark with specified name from
+// specified bookmarks.
+func FindBookmark(bookmarks []Bookmark, name string) (Bookmark, bool) {
+	for _, b := range bookmarks {
+		if b.Name == name {
+			return b, true
+		}
+	}
+	return Bookmark{}, false
+}
+
+// BookmarksPath returns path to the bookmarks file.
+// Bookmarks file is stored in the same directory as
+// the config file.
+func BookmarksPath() string {
+	return filepath.Join(filepath.Dir(Path), BookmarksFileName)
+}
@@ -25,10 +25,14 @@
 package config
 
 import (
-	"bufio"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/isangeles/flame/data/text"
 
@@ -36,10 +40,18 @@ import (
 )
 
 const (
-	ConfigFileName = ".burnsh"
+	ConfigFileName      = ".burnsh"
+	HistoryFileName     = ".burnsh_history"
+	CredentialsFileName = ".burnsh_credentials"
+	DirName             = "burnsh"
+	DirConfigFileName   = "config"
+	ProfilesDirName     = "profiles"
+	CommentPrefix       = "#"
 )
 
 var (
+	Path        = ConfigFileName
+	Profile     = ""
 	Module      = ""
 	ModulesPath = "data/modules"
 	Lang        = "english"
@@ -49,19 +61,121 @@ var (
 	ServerPass  = ""
 	ServerTLS   = false
 	Debug       = false
+	// Interval for batching server requests in milliseconds,
+	// 0 disables batching.
+	ServerBatch = 0
+	// Interval between pings sent to the server and maximal
+	// time to wait for the pong, in seconds, 0 interval
+	// disables pings.
+	ServerPing        = 30
+	ServerPingTimeout = 10
+	// TLS options for the server connection.
+	ServerCA       = ""
+	ServerCert     = ""
+	ServerKey      = ""
+	ServerName     = ""
+	ServerInsecure = false
+	// URL path of the server endpoint, additional handshake
+	// headers in form of 'name=value' and proxy URL.
+	ServerPath    = ""
+	ServerHeaders []string
+	ServerProxy   = ""
+	// Server connection timeout in seconds.
+	ServerTimeout = 15
+	// Autosave interval in minutes, 0 disables autosave.
+	AutosaveInterval = 0
+	AutosaveSlots    = 3
+	// Party formation and distance between party members
+	// in formation, empty formation disables following.
+	Formation        = ""
+	FormationSpacing = 30.0
+	// Maximal number of input history lines, 0 means
+	// no limit.
+	HistorySize = 1000
 )
 
-// Load loads the CLI config file.
+var (
+	// Values loaded from the config file.
+	fileConf = make(map[string][]string)
+	// Lines of the config file.
+	fileLines []string
+	// Default config values.
+	defaults = values()
+	// Deprecated keys removed from the config file on save,
+	// after values were moved elsewhere.
+	droppedKeys = make(map[string]bool)
+)
+
+// Load loads the CLI config file and applies values
+// from environment variables and command line flags.
+// Config file is searched in the current directory and
+// in the user config directory, unless config file path
+// or profile is specified with a flag or environment
+// variable.
 func Load() error {
-	file, err := os.Open(ConfigFileName)
+	if v, ok := lookupOverride("profile"); ok {
+		Profile = v
+	}
+	Path = findPath()
+	if v, ok := lookupOverride("config"); ok {
+		Path = v
+	}
+	return load()
+}
+
+// LoadProfile loads config of profile with specified name.
+// Empty name stands for the default profile.
+func LoadProfile(name string) error {
+	Profile = name
+	Path = findPath()
+	return load()
+}
+
+// load loads config values from the config file
+// under the current config path.
+func load() error {
+	fileConf = make(map[string][]string)
+	fileLines = nil
+	overridden = make(map[string]map[int]bool)
+	droppedKeys = make(map[string]bool)
+	resetValues()
+	err := loadFile()
+	oerr := applyOverrides()
+	if err == nil {
+		err = oerr
+	}
+	return err
+}
+
+// loadFile loads values from the config file.
+// Comment lines, starting with '#', are skipped.
+func loadFile() error {
+	data, err := os.ReadFile(Path)
 	if err != nil {
-		return fmt.Errorf("unable to open config file: %v", err)
+		return fmt.Errorf("unable to read config file: %v", err)
 	}
-	defer file.Close()
-	conf, err := text.UnmarshalConfig(file)
+	fileLines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	confLines := make([]string, 0)
+	for _, l := range fileLines {
+		if !strings.HasPrefix(strings.TrimSpace(l), CommentPrefix) {
+			confLines = append(confLines, l)
+		}
+	}
+	conf, err := text.UnmarshalConfig(strings.NewReader(strings.Join(confLines, "\n")))
 	if err != nil {
 		return fmt.Errorf("unable to unmarshal config file: %v", err)
 	}
+	fileConf = conf
+	err = setValues(conf)
+	if err != nil {
+		return err
+	}
+	log.Dbg.Println("Config file loaded")
+	return nil
+}
+
+// setValues sets config values from specified config map.
+func setValues(conf map[string][]string) (err error) {
 	if len(conf["module"]) > 0 {
 		Module = conf["module"][0]
 	}
@@ -79,44 +193,325 @@ func Load() error {
 	if len(conf["server-tls"]) > 0 {
 		ServerTLS = conf["server-tls"][0] == "true"
 	}
+	if len(conf["server-ca"]) > 0 {
+		ServerCA = conf["server-ca"][0]
+	}
+	if len(conf["server-cert"]) > 1 {
+		ServerCert = conf["server-cert"][0]
+		ServerKey = conf["server-cert"][1]
+	}
+	if len(conf["server-name"]) > 0 {
+		ServerName = conf["server-name"][0]
+	}
+	if len(conf["server-insecure"]) > 0 {
+		ServerInsecure = conf["server-insecure"][0] == "true"
+	}
+	if len(conf["server-path"]) > 0 {
+		ServerPath = conf["server-path"][0]
+	}
+	if len(conf["server-header"]) > 0 {
+		ServerHeaders = nil
+		for _, h := range conf["server-header"] {
+			if len(h) > 0 {
+				ServerHeaders = append(ServerHeaders, h)
+			}
+		}
+	}
+	if len(conf["server-proxy"]) == 1 {
+		ServerProxy = conf["server-proxy"][0]
+	}
+	if len(conf["server-proxy"]) > 2 {
+		ServerProxy = fmt.Sprintf("%s://%s", conf["server-proxy"][0],
+			net.JoinHostPort(conf["server-proxy"][1], conf["server-proxy"][2]))
+	}
+	if len(conf["server-timeout"]) > 0 {
+		ServerTimeout, err = strconv.Atoi(conf["server-timeout"][0])
+		if err != nil {
+			return fmt.Errorf("invalid server timeout: %v", err)
+		}
+	}
 	if len(conf["debug"]) > 0 {
 		Debug = conf["debug"][0] == "true"
 	}
-	log.Dbg.Println("Config file loaded")
+	if len(conf["server-batch"]) > 0 {
+		ServerBatch, err = strconv.Atoi(conf["server-batch"][0])
+		if err != nil {
+			return fmt.Errorf("invalid server batch interval: %v", err)
+		}
+	}
+	if len(conf["server-ping"]) > 0 {
+		ServerPing, err = strconv.Atoi(conf["server-ping"][0])
+		if err != nil {
+			return fmt.Errorf("invalid server ping interval: %v", err)
+		}
+	}
+	if len(conf["server-ping"]) > 1 {
+		ServerPingTimeout, err = strconv.Atoi(conf["server-ping"][1])
+		if err != nil {
+			return fmt.Errorf("invalid server ping timeout: %v", err)
+		}
+	}
+	if len(conf["autosave"]) > 0 {
+		AutosaveInterval, err = strconv.Atoi(conf["autosave"][0])
+		if err != nil {
+			return fmt.Errorf("invalid autosave interval: %v", err)
+		}
+	}
+	if len(conf["autosave"]) > 1 {
+		AutosaveSlots, err = strconv.Atoi(conf["autosave"][1])
+		if err != nil {
+			return fmt.Errorf("invalid autosave slots number: %v", err)
+		}
+	}
+	if len(conf["formation"]) > 0 {
+		Formation = conf["formation"][0]
+	}
+	if len(conf["formation"]) > 1 {
+		FormationSpacing, err = strconv.ParseFloat(conf["formation"][1], 64)
+		if err != nil {
+			return fmt.Errorf("invalid formation spacing: %v", err)
+		}
+	}
+	if len(conf["history-size"]) > 0 {
+		HistorySize, err = strconv.Atoi(conf["history-size"][0])
+		if err != nil {
+			return fmt.Errorf("invalid history size: %v", err)
+		}
+	}
 	return nil
 }
 
-// Save saves current config values in the config file.
-func Save() error {
-	// Create file.
-	file, err := os.Create(ConfigFileName)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-	// Marshal config.
+// resetValues sets all config values to defaults.
+// Values without defaults in the config map are reset
+// separately, since setValues skips empty values.
+func resetValues() {
+	setValues(defaults)
+	ServerLogin = ""
+	ServerPass = ""
+	ServerHeaders = nil
+}
+
+// values returns map with current config values.
+func values() map[string][]string {
 	conf := make(map[string][]string)
 	conf["module"] = []string{Module}
 	conf["lang"] = []string{Lang}
 	conf["server"] = []string{ServerHost, ServerPort}
-	conf["server-user"] = []string{ServerLogin, ServerPass}
 	conf["server-tls"] = []string{fmt.Sprintf("%v", ServerTLS)}
+	conf["server-ca"] = []string{ServerCA}
+	conf["server-cert"] = []string{ServerCert, ServerKey}
+	conf["server-name"] = []string{ServerName}
+	conf["server-insecure"] = []string{fmt.Sprintf("%v", ServerInsecure)}
+	conf["server-path"] = []string{ServerPath}
+	conf["server-header"] = append([]string{}, ServerHeaders...)
+	conf["server-proxy"] = proxyValues(ServerProxy)
+	conf["server-timeout"] = []string{fmt.Sprintf("%d", ServerTimeout)}
 	conf["debug"] = []string{fmt.Sprintf("%v", Debug)}
-	confText := text.MarshalConfig(conf)
+	conf["server-batch"] = []string{fmt.Sprintf("%d", ServerBatch)}
+	conf["server-ping"] = []string{fmt.Sprintf("%d", ServerPing),
+		fmt.Sprintf("%d", ServerPingTimeout)}
+	conf["autosave"] = []string{fmt.Sprintf("%d", AutosaveInterval),
+		fmt.Sprintf("%d", AutosaveSlots)}
+	conf["formation"] = []string{Formation, fmt.Sprintf("%g", FormationSpacing)}
+	conf["history-size"] = []string{fmt.Sprintf("%d", HistorySize)}
+	return conf
+}
+
+// proxyValues returns config values for specified proxy URL.
+// Proxy URL is stored in form of scheme, host and port values,
+// so it doesn't contain the ':' character.
+func proxyValues(proxy string) []string {
+	u, err := url.Parse(proxy)
+	if err != nil || len(u.Scheme) < 1 || len(u.Port()) < 1 {
+		return []string{proxy}
+	}
+	return []string{u.Scheme, u.Hostname(), u.Port()}
+}
+
+// Save saves current config values in the config file.
+// Only values changed since the config file was loaded are
+// written, other lines of the file, including unknown keys
+// and comments, are preserved.
+// Values overridden by environment variables or command
+// line flags are not saved.
+func Save() error {
+	conf := values()
+	keys := make([]string, 0, len(conf))
+	for k := range conf {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(fileLines))
+	for _, l := range fileLines {
+		if !droppedKeys[lineKey(l)] {
+			lines = append(lines, l)
+		}
+	}
+	for _, k := range keys {
+		value := fileOverridden(k, conf[k])
+		if !changed(k, value) {
+			continue
+		}
+		line := fmt.Sprintf("%s:%s", k, strings.Join(value, ";"))
+		found := false
+		for i, l := range lines {
+			if lineKey(l) == k {
+				lines[i] = line
+				found = true
+			}
+		}
+		if !found {
+			lines = append(lines, line)
+		}
+		fileConf[k] = value
+	}
 	// Write to file.
-	w := bufio.NewWriter(file)
-	w.WriteString(confText)
-	// Save.
-	w.Flush()
+	err := os.MkdirAll(filepath.Dir(Path), 0755)
+	if err != nil {
+		return fmt.Errorf("unable to create config directory: %v", err)
+	}
+	err = os.WriteFile(Path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+	if err != nil {
+		return fmt.Errorf("unable to write config file: %v", err)
+	}
+	fileLines = lines
 	log.Dbg.Println("Config file saved")
 	return nil
 }
 
+// fileOverridden returns specified value of config key with
+// specified name, with values overridden by environment
+// variables or command line flags replaced by values from
+// the config file, or default values.
+func fileOverridden(key string, value []string) []string {
+	if len(overridden[key]) < 1 {
+		return value
+	}
+	fileValue, ok := fileConf[key]
+	if !ok {
+		fileValue = defaults[key]
+	}
+	value = append([]string{}, value...)
+	for i := range overridden[key] {
+		if i >= len(value) {
+			continue
+		}
+		value[i] = ""
+		if i < len(fileValue) {
+			value[i] = fileValue[i]
+		}
+	}
+	return value
+}
+
+// changed checks if specified value of config key with
+// specified name differs from the value in the config
+// file, or from the default value if the config file
+// doesn't contain such key.
+// All values are treated as changed if there is no
+// config file yet.
+func changed(key string, value []string) bool {
+	if fileLines == nil {
+		return true
+	}
+	fileValue, ok := fileConf[key]
+	if !ok {
+		fileValue = defaults[key]
+	}
+	return strings.Join(fileValue, ";") != strings.Join(value, ";")
+}
+
+// DropServerUser removes server login and password from the
+// config values, deprecated 'server-user' key is removed from
+// the config file on the next save.
+// Server credentials should be moved to the credentials file
+// first.
+func DropServerUser() {
+	ServerLogin = ""
+	ServerPass = ""
+	droppedKeys["server-user"] = true
+}
+
+// lineKey returns config key from specified config
+// file line.
+func lineKey(line string) string {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, CommentPrefix) {
+		return ""
+	}
+	return strings.SplitN(line, ":", 2)[0]
+}
+
+// findPath returns path to the config file of the
+// current profile.
+// Config file of the default profile is searched in the
+// current directory first, and then in the user config
+// directory.
+func findPath() string {
+	if len(Profile) > 0 {
+		return ProfilePath(Profile)
+	}
+	if _, err := os.Stat(ConfigFileName); err == nil {
+		return ConfigFileName
+	}
+	return filepath.Join(Dir(), DirConfigFileName)
+}
+
+// Dir returns path to the burnsh directory in the user
+// config directory, e.g. '$XDG_CONFIG_HOME/burnsh'.
+func Dir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		log.Err.Printf("Unable to find user config directory: %v", err)
+		return ""
+	}
+	return filepath.Join(dir, DirName)
+}
+
+// ProfilePath returns path to the config file of profile
+// with specified name.
+func ProfilePath(name string) string {
+	return filepath.Join(Dir(), ProfilesDirName, name, DirConfigFileName)
+}
+
+// Profiles returns names of all profiles in the user
+// config directory.
+func Profiles() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(Dir(), ProfilesDirName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read profiles directory: %v", err)
+	}
+	profiles := make([]string, 0)
+	for _, e := range entries {
+		if e.IsDir() {
+			profiles = append(profiles, e.Name())
+		}
+	}
+	return profiles, nil
+}
+
 // ModulePath returns path to the directory of current module.
 func ModulePath() string {
 	return filepath.Join(ModulesPath, Module)
 }
 
+// HistoryPath returns path to the input history file.
+// History file is stored in the same directory as
+// the config file.
+func HistoryPath() string {
+	return filepath.Join(filepath.Dir(Path), HistoryFileName)
+}
+
+// CredentialsPath returns path to the credentials file.
+// Credentials file is stored in the same directory as
+// the config file.
+func CredentialsPath() string {
+	return filepath.Join(filepath.Dir(Path), CredentialsFileName)
+}
+
 // LangPath returns path to the CLI lang directory.
 func LangPath() string {
 	return filepath.Join("data/lang", Lang)
@@ -129,5 +524,5 @@ func ScriptsPath() string {
 
 // Multiplayer checks if multiplayer mode is active.
 func Multiplayer() bool {
-	return len(ServerHost + ServerPort) > 0
+	return len(ServerHost+ServerPort) > 0
 }
@@ -0,0 +1,340 @@
+/*
+ * config_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadOverrides tests overriding config file values
+// with environment variables and command line flags.
+func TestLoadOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	err := os.WriteFile(path, []byte("module:mod_file\nlang:lang_file\n"), 0644)
+	if err != nil {
+		t.Fatalf("Unable to write config file: %v", err)
+	}
+	t.Setenv("BURNSH_CONFIG", path)
+	t.Setenv("BURNSH_MODULE", "mod_env")
+	t.Setenv("BURNSH_LANG", "lang_env")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	SetFlags(fs)
+	err = fs.Parse([]string{"-lang", "lang_flag", "-debug"})
+	if err != nil {
+		t.Fatalf("Unable to parse flags: %v", err)
+	}
+	defer func() { flagValues = make(map[*override]string) }()
+	// Test.
+	err = Load()
+	if err != nil {
+		t.Fatalf("Unable to load config: %v", err)
+	}
+	if Path != path {
+		t.Errorf("Config path invalid: %s != %s", Path, path)
+	}
+	if Module != "mod_env" {
+		t.Errorf("Module invalid: %s != mod_env", Module)
+	}
+	if Lang != "lang_flag" {
+		t.Errorf("Lang invalid: %s != lang_flag", Lang)
+	}
+	if !Debug {
+		t.Errorf("Debug mode not enabled")
+	}
+	// Test save.
+	err = Save()
+	if err != nil {
+		t.Fatalf("Unable to save config: %v", err)
+	}
+	Module, Lang = "", ""
+	flagValues = make(map[*override]string)
+	os.Unsetenv("BURNSH_MODULE")
+	os.Unsetenv("BURNSH_LANG")
+	err = Load()
+	if err != nil {
+		t.Fatalf("Unable to load saved config: %v", err)
+	}
+	if Module != "mod_file" || Lang != "lang_file" {
+		t.Errorf("Saved config values invalid: %s, %s != mod_file, lang_file",
+			Module, Lang)
+	}
+}
+
+// TestSaveServerOverride tests saving server port with
+// overridden server host.
+func TestSaveServerOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	err := os.WriteFile(path, []byte("server:host_file;1000\n"), 0644)
+	if err != nil {
+		t.Fatalf("Unable to write config file: %v", err)
+	}
+	t.Setenv("BURNSH_CONFIG", path)
+	t.Setenv("BURNSH_SERVER_HOST", "host_env")
+	err = Load()
+	if err != nil {
+		t.Fatalf("Unable to load config: %v", err)
+	}
+	if ServerHost != "host_env" {
+		t.Errorf("Server host invalid: %s != host_env", ServerHost)
+	}
+	// Test.
+	ServerPort = "2000"
+	err = Save()
+	if err != nil {
+		t.Fatalf("Unable to save config: %v", err)
+	}
+	os.Unsetenv("BURNSH_SERVER_HOST")
+	err = Load()
+	if err != nil {
+		t.Fatalf("Unable to load saved config: %v", err)
+	}
+	if ServerHost != "host_file" || ServerPort != "2000" {
+		t.Errorf("Saved server invalid: %s, %s != host_file, 2000",
+			ServerHost, ServerPort)
+	}
+}
+
+// TestLoadBoolOverrides tests parsing values of bool
+// config overrides.
+func TestLoadBoolOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	err := os.WriteFile(path, []byte("debug:false\n"), 0644)
+	if err != nil {
+		t.Fatalf("Unable to write config file: %v", err)
+	}
+	t.Setenv("BURNSH_CONFIG", path)
+	t.Setenv("BURNSH_DEBUG", "1")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	SetFlags(fs)
+	defer func() { flagValues = make(map[*override]string) }()
+	err = fs.Parse([]string{"-server-tls=yes"})
+	if err == nil {
+		t.Errorf("No error for invalid bool flag value")
+	}
+	// Test.
+	err = Load()
+	if err != nil {
+		t.Fatalf("Unable to load config: %v", err)
+	}
+	if !Debug {
+		t.Errorf("Debug mode not enabled")
+	}
+	t.Setenv("BURNSH_SERVER_TLS", "maybe")
+	err = Load()
+	if err == nil || !strings.Contains(err.Error(), "BURNSH_SERVER_TLS") {
+		t.Errorf("Error for invalid bool environment value invalid: %v", err)
+	}
+	if ServerTLS || !Debug {
+		t.Errorf("Bool values invalid: %v, %v != false, true", ServerTLS, Debug)
+	}
+}
+
+// TestSavePreserve tests preserving unknown keys and
+// comments of the config file on save.
+func TestSavePreserve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	conf := "# Test config.\nmodule:mod_test\nunknown:value\n"
+	err := os.WriteFile(path, []byte(conf), 0644)
+	if err != nil {
+		t.Fatalf("Unable to write config file: %v", err)
+	}
+	t.Setenv("BURNSH_CONFIG", path)
+	err = Load()
+	if err != nil {
+		t.Fatalf("Unable to load config: %v", err)
+	}
+	Lang = "lang_test"
+	err = Save()
+	if err != nil {
+		t.Fatalf("Unable to save config: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unable to read saved config: %v", err)
+	}
+	expConf := conf + "lang:lang_test\n"
+	if string(data) != expConf {
+		t.Errorf("Saved config invalid: %q != %q", data, expConf)
+	}
+}
+
+// TestSaveServerUser tests keeping deprecated server
+// credentials in the config file until dropped.
+func TestSaveServerUser(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	conf := "server-user:user_test;pass_test\n"
+	err := os.WriteFile(path, []byte(conf), 0644)
+	if err != nil {
+		t.Fatalf("Unable to write config file: %v", err)
+	}
+	t.Setenv("BURNSH_CONFIG", path)
+	err = Load()
+	if err != nil {
+		t.Fatalf("Unable to load config: %v", err)
+	}
+	if ServerLogin != "user_test" || ServerPass != "pass_test" {
+		t.Errorf("Server user invalid: %s %s", ServerLogin, ServerPass)
+	}
+	// Test.
+	err = Save()
+	if err != nil {
+		t.Fatalf("Unable to save config: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unable to read saved config: %v", err)
+	}
+	if string(data) != conf {
+		t.Errorf("Saved config invalid: %q != %q", data, conf)
+	}
+	DropServerUser()
+	err = Save()
+	if err != nil {
+		t.Fatalf("Unable to save config: %v", err)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unable to read saved config: %v", err)
+	}
+	if strings.Contains(string(data), "server-user") {
+		t.Errorf("Dropped server user saved: %q", data)
+	}
+}
+
+// TestLoadProfile tests loading config profiles from
+// the user config directory.
+func TestLoadProfile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("BURNSH_CONFIG", "")
+	os.Unsetenv("BURNSH_CONFIG")
+	err := LoadProfile("profile_test")
+	if err == nil {
+		t.Errorf("No error for missing profile config file")
+	}
+	Module = "mod_profile"
+	err = Save()
+	if err != nil {
+		t.Fatalf("Unable to save profile config: %v", err)
+	}
+	Module = ""
+	err = LoadProfile("profile_test")
+	if err != nil {
+		t.Fatalf("Unable to load profile: %v", err)
+	}
+	if Module != "mod_profile" {
+		t.Errorf("Profile module invalid: %s != mod_profile", Module)
+	}
+	profiles, err := Profiles()
+	if err != nil {
+		t.Fatalf("Unable to list profiles: %v", err)
+	}
+	if len(profiles) != 1 || profiles[0] != "profile_test" {
+		t.Errorf("Profiles invalid: %v", profiles)
+	}
+}
+
+// TestLoadProfileReset tests resetting values from the
+// previous profile on profile load.
+func TestLoadProfileReset(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("BURNSH_CONFIG", "")
+	os.Unsetenv("BURNSH_CONFIG")
+	err := LoadProfile("profile_test")
+	if err == nil {
+		t.Errorf("No error for missing profile config file")
+	}
+	err = os.MkdirAll(filepath.Dir(Path), 0755)
+	if err != nil {
+		t.Fatalf("Unable to create profile directory: %v", err)
+	}
+	conf := "server-user:user_test;pass_test\nserver-header:X-Test=test\nserver-timeout:5\n"
+	err = os.WriteFile(Path, []byte(conf), 0644)
+	if err != nil {
+		t.Fatalf("Unable to write profile config file: %v", err)
+	}
+	err = LoadProfile("profile_test")
+	if err != nil {
+		t.Fatalf("Unable to load profile: %v", err)
+	}
+	if ServerLogin != "user_test" || len(ServerHeaders) != 1 || ServerTimeout != 5 {
+		t.Fatalf("Profile values invalid: %s %v %d", ServerLogin, ServerHeaders, ServerTimeout)
+	}
+	// Test.
+	err = LoadProfile("profile_empty")
+	if err == nil {
+		t.Errorf("No error for missing profile config file")
+	}
+	if ServerLogin != "" || ServerPass != "" {
+		t.Errorf("Server user not reset: %s %s", ServerLogin, ServerPass)
+	}
+	if len(ServerHeaders) > 0 {
+		t.Errorf("Server headers not reset: %v", ServerHeaders)
+	}
+	if ServerTimeout != 15 {
+		t.Errorf("Server timeout not reset: %d != 15", ServerTimeout)
+	}
+}
+
+// TestBookmarks tests saving and loading bookmarked servers.
+func TestBookmarks(t *testing.T) {
+	Path = filepath.Join(t.TempDir(), ConfigFileName)
+	bookmarks, err := Bookmarks()
+	if err != nil || len(bookmarks) > 0 {
+		t.Fatalf("Bookmarks without bookmarks file invalid: %v: %v", bookmarks, err)
+	}
+	bookmarks = []Bookmark{
+		{Name: "local", Host: "localhost", Port: "8000"},
+		{Name: "staging", Host: "fire.test", Port: "443", TLS: true, Login: "user_test"},
+	}
+	err = SaveBookmarks(bookmarks)
+	if err != nil {
+		t.Fatalf("Unable to save bookmarks: %v", err)
+	}
+	loaded, err := Bookmarks()
+	if err != nil {
+		t.Fatalf("Unable to load bookmarks: %v", err)
+	}
+	if len(loaded) != len(bookmarks) {
+		t.Fatalf("Bookmarks number invalid: %d != %d", len(loaded), len(bookmarks))
+	}
+	for i, b := range bookmarks {
+		if loaded[i] != b {
+			t.Errorf("Bookmark invalid: %v != %v", loaded[i], b)
+		}
+	}
+	b, ok := FindBookmark(loaded, "staging")
+	if !ok || b.Host != "fire.test" {
+		t.Errorf("Bookmark not found: staging")
+	}
+}
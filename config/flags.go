@@ -0,0 +1,177 @@
+/*
+ * flags.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Struct for config value that can be overridden
+// with command line flag or environment variable.
+type override struct {
+	flag    string
+	env     string
+	usage   string
+	bool    bool
+	confKey string
+	// Index of the value under the config key.
+	confIndex int
+	set       func(v string)
+}
+
+// Type for command line flag with config value.
+type overrideFlag struct {
+	override *override
+}
+
+var (
+	// Config values that can be overridden.
+	overrides = []*override{
+		{flag: "config", env: "BURNSH_CONFIG", usage: "path to the config file"},
+		{flag: "profile", env: "BURNSH_PROFILE", usage: "name of the config profile"},
+		{flag: "module", env: "BURNSH_MODULE", usage: "ID of the module to load",
+			confKey: "module", set: func(v string) { Module = v }},
+		{flag: "modules-path", env: "BURNSH_MODULES_PATH", usage: "path to the modules directory",
+			set: func(v string) { ModulesPath = v }},
+		{flag: "lang", env: "BURNSH_LANG", usage: "language of the interface",
+			confKey: "lang", set: func(v string) { Lang = v }},
+		{flag: "server-host", env: "BURNSH_SERVER_HOST", usage: "game server host",
+			confKey: "server", set: func(v string) { ServerHost = v }},
+		{flag: "server-port", env: "BURNSH_SERVER_PORT", usage: "game server port",
+			confKey: "server", confIndex: 1, set: func(v string) { ServerPort = v }},
+		{flag: "server-tls", env: "BURNSH_SERVER_TLS", usage: "use TLS for the game server connection",
+			bool: true, confKey: "server-tls", set: func(v string) { ServerTLS = v == "true" }},
+		{flag: "debug", env: "BURNSH_DEBUG", usage: "enable debug mode",
+			bool: true, confKey: "debug", set: func(v string) { Debug = v == "true" }},
+	}
+	// Values from command line flags.
+	flagValues = make(map[*override]string)
+	// Indices of overridden values of config file keys.
+	overridden = make(map[string]map[int]bool)
+)
+
+// String returns empty string, flag has no default value.
+func (f overrideFlag) String() string {
+	return ""
+}
+
+// Set sets specified value as flag value.
+// Returns an error if the value is invalid.
+func (f overrideFlag) Set(v string) error {
+	v, err := f.override.value(v)
+	if err != nil {
+		return err
+	}
+	flagValues[f.override] = v
+	return nil
+}
+
+// IsBoolFlag checks if flag can be specified without
+// a value.
+func (f overrideFlag) IsBoolFlag() bool {
+	return f.override != nil && f.override.bool
+}
+
+// SetFlags defines command line flags for config values
+// in specified flag set.
+// Values from flags override values from the config file
+// and environment variables.
+func SetFlags(fs *flag.FlagSet) {
+	for _, o := range overrides {
+		usage := fmt.Sprintf("%s (env %s)", o.usage, o.env)
+		fs.Var(overrideFlag{o}, o.flag, usage)
+	}
+}
+
+// applyOverrides sets config values from environment variables
+// and command line flags.
+// Invalid values are skipped, error is returned for the first
+// invalid value.
+func applyOverrides() (err error) {
+	for _, o := range overrides {
+		if o.set == nil {
+			continue
+		}
+		v, src, ok := overrideValue(o)
+		if !ok {
+			continue
+		}
+		v, verr := o.value(v)
+		if verr != nil {
+			if err == nil {
+				err = fmt.Errorf("invalid %s value: %v", src, verr)
+			}
+			continue
+		}
+		o.set(v)
+		if len(o.confKey) < 1 {
+			continue
+		}
+		if overridden[o.confKey] == nil {
+			overridden[o.confKey] = make(map[int]bool)
+		}
+		overridden[o.confKey][o.confIndex] = true
+	}
+	return
+}
+
+// value returns config value for specified override value.
+// Values of bool overrides are parsed with strconv.ParseBool,
+// like values of bool flags, and returned as 'true' or 'false'.
+func (o *override) value(v string) (string, error) {
+	if !o.bool {
+		return v, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return "", fmt.Errorf("parse error")
+	}
+	return strconv.FormatBool(b), nil
+}
+
+// lookupOverride returns value of override with specified
+// flag name, from command line flag or environment variable.
+func lookupOverride(name string) (string, bool) {
+	for _, o := range overrides {
+		if o.flag == name {
+			v, _, ok := overrideValue(o)
+			return v, ok
+		}
+	}
+	return "", false
+}
+
+// overrideValue returns value for specified config value
+// override, from command line flag or environment variable,
+// and the name of the value source, flag or variable.
+func overrideValue(o *override) (string, string, bool) {
+	if v, ok := flagValues[o]; ok {
+		return v, "-" + o.flag + " flag", true
+	}
+	v, ok := os.LookupEnv(o.env)
+	return v, o.env + " variable", ok
+}
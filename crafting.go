@@ -1,7 +1,7 @@
 /*
  * creafting.go
  *
- * Copyright 2019-2021 Dariusz Sikora <dev@isangeles.pl>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -37,18 +37,30 @@ import (
 
 // craftingDialog starts CLI dialog for
 // active PC crafting.
-func craftingDialog() error {
+// Recipe to make can be specified by ID as an argument, in
+// that case the dialog skips the recipe prompt.
+func craftingDialog(args ...string) error {
 	if activeGame == nil {
 		msg := lang.Text("no_game_err")
 		return fmt.Errorf(msg)
 	}
-	if activeGame.ActivePlayer() == nil {
+	if actingPlayer() == nil {
 		msg := lang.Text("no_pc_err")
 		return fmt.Errorf(msg)
 	}
+	if len(args) > 0 {
+		for _, r := range actingPlayer().Crafting().Recipes() {
+			if r.ID() == args[0] {
+				actingPlayer().Use(r)
+				return nil
+			}
+		}
+		msg := lang.Text("crafting_recipe_not_found_err")
+		return fmt.Errorf("%s: %s", msg, args[0])
+	}
 	for {
 		// Select recipe.
-		recipe, err := recipeDialog(activeGame.ActivePlayer().Character)
+		recipe, err := recipeDialog(actingPlayer().Character)
 		if err != nil {
 			fmt.Printf("%v\n", err)
 			break
@@ -98,7 +110,7 @@ func craftingDialog() error {
 			break
 		}
 		if ans == 1 {
-			activeGame.ActivePlayer().Use(recipe)
+			actingPlayer().Use(recipe)
 			break
 		}
 	}
@@ -0,0 +1,192 @@
+/*
+ * credentials.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+// Package for encrypted storage of game server credentials.
+// Credentials are stored in a file encrypted with AES-GCM,
+// with key derived from user passphrase by PBKDF2.
+package credentials
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	fileMagic  = "BURNSHC1"
+	saltSize   = 16
+	keySize    = 32
+	iterations = 100000
+)
+
+var ErrInvalidPassphrase = errors.New("invalid passphrase or corrupted credentials file")
+
+// Struct for game server credentials.
+type Credentials struct {
+	Login string `json:"login"`
+	Pass  string `json:"pass"`
+}
+
+// Struct for credentials store.
+// Credentials are stored by server address.
+type Store struct {
+	entries map[string]Credentials
+}
+
+// NewStore creates new empty credentials store.
+func NewStore() *Store {
+	s := Store{entries: make(map[string]Credentials)}
+	return &s
+}
+
+// Get returns credentials for server with specified address.
+func (s *Store) Get(server string) (Credentials, bool) {
+	c, ok := s.entries[server]
+	return c, ok
+}
+
+// Set sets specified credentials for server with specified
+// address.
+func (s *Store) Set(server string, c Credentials) {
+	s.entries[server] = c
+}
+
+// Remove removes credentials for server with specified
+// address.
+func (s *Store) Remove(server string) {
+	delete(s.entries, server)
+}
+
+// Servers returns addresses of all servers with stored
+// credentials.
+func (s *Store) Servers() []string {
+	servers := make([]string, 0, len(s.entries))
+	for server := range s.entries {
+		servers = append(servers, server)
+	}
+	sort.Strings(servers)
+	return servers
+}
+
+// Load loads credentials store from file with specified
+// path and decrypts it with specified passphrase.
+// Returns ErrInvalidPassphrase if the file can't be
+// decrypted.
+func Load(path, passphrase string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read credentials file: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte(fileMagic)) {
+		return nil, fmt.Errorf("invalid credentials file format")
+	}
+	data = data[len(fileMagic):]
+	if len(data) < saltSize {
+		return nil, ErrInvalidPassphrase
+	}
+	salt, data := data[:saltSize], data[saltSize:]
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrInvalidPassphrase
+	}
+	nonce, data := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, data, []byte(fileMagic))
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+	s := NewStore()
+	err = json.Unmarshal(plain, &s.entries)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal credentials: %v", err)
+	}
+	return s, nil
+}
+
+// Save encrypts credentials store with specified passphrase
+// and saves it in file with specified path.
+// File is readable and writable only by the owner.
+func (s *Store) Save(path, passphrase string) error {
+	plain, err := json.Marshal(s.entries)
+	if err != nil {
+		return fmt.Errorf("unable to marshal credentials: %v", err)
+	}
+	salt := make([]byte, saltSize)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return fmt.Errorf("unable to generate salt: %v", err)
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return fmt.Errorf("unable to generate nonce: %v", err)
+	}
+	data := append([]byte(fileMagic), salt...)
+	data = append(data, nonce...)
+	data = gcm.Seal(data, nonce, plain, []byte(fileMagic))
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return fmt.Errorf("unable to create credentials directory: %v", err)
+	}
+	err = os.WriteFile(path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write credentials file: %v", err)
+	}
+	// Restrict permissions of already existing file.
+	err = os.Chmod(path, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to set credentials file permissions: %v", err)
+	}
+	return nil
+}
+
+// newGCM creates AES-GCM cipher with key derived from
+// specified passphrase and salt.
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key := pbkdf2.Key([]byte(passphrase), salt, iterations, keySize, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create cipher: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create GCM cipher: %v", err)
+	}
+	return gcm, nil
+}
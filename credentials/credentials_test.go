@@ -0,0 +1,71 @@
+/*
+ * credentials_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package credentials
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSaveLoad tests saving and loading encrypted
+// credentials.
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	store := NewStore()
+	store.Set("localhost:8000", Credentials{"user", "secret_pass"})
+	err := store.Save(path, "passphrase")
+	if err != nil {
+		t.Fatalf("Unable to save credentials: %v", err)
+	}
+	// Test file.
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Unable to stat credentials file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Credentials file permissions invalid: %v", info.Mode().Perm())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unable to read credentials file: %v", err)
+	}
+	if bytes.Contains(data, []byte("secret_pass")) {
+		t.Errorf("Credentials file contains plain password")
+	}
+	// Test load.
+	_, err = Load(path, "invalid")
+	if err != ErrInvalidPassphrase {
+		t.Errorf("Invalid passphrase error invalid: %v", err)
+	}
+	loaded, err := Load(path, "passphrase")
+	if err != nil {
+		t.Fatalf("Unable to load credentials: %v", err)
+	}
+	c, ok := loaded.Get("localhost:8000")
+	if !ok || c.Login != "user" || c.Pass != "secret_pass" {
+		t.Errorf("Loaded credentials invalid: %v", c)
+	}
+}
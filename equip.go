@@ -1,7 +1,7 @@
 /*
  * equip.go
  *
- * Copyright 2021-2023 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2021-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -34,25 +34,36 @@ import (
 )
 
 // equipDialog starts CLI dialog for equip action.
-func equipDialog() error {
+// Item to equip or unequip can be specified as an argument
+// in the form of [ID]#[serial], in that case the dialog
+// skips the item prompt.
+func equipDialog(args ...string) error {
 	if activeGame == nil {
 		msg := lang.Text("no_game_err")
 		return fmt.Errorf(msg)
 	}
-	if activeGame.ActivePlayer() == nil {
+	if actingPlayer() == nil {
 		msg := lang.Text("no_pc_err")
 		return fmt.Errorf(msg)
 	}
-	// List items.
-	fmt.Printf("%s:\n", lang.Text("equip_items"))
 	items := make([]item.Equiper, 0)
-	for _, it := range activeGame.ActivePlayer().Inventory().Items() {
+	for _, it := range actingPlayer().Inventory().Items() {
 		if eit, ok := it.Item.(item.Equiper); ok {
 			items = append(items, eit)
 		}
 	}
+	if len(args) > 0 {
+		for _, it := range items {
+			if matchIDSerial(args[0], it.ID(), it.Serial()) {
+				return equip(it)
+			}
+		}
+		return fmt.Errorf("%s: %s", lang.Text("item_not_found_err"), args[0])
+	}
+	// List items.
+	fmt.Printf("%s:\n", lang.Text("equip_items"))
 	for i, it := range items {
-		if activeGame.ActivePlayer().Equipment().Equiped(it) {
+		if actingPlayer().Equipment().Equiped(it) {
 			fmt.Printf("[%d]%s[e]\n", i, lang.Text(it.ID()))
 		} else {
 			fmt.Printf("[%d]%s\n", i, lang.Text(it.ID()))
@@ -76,15 +87,20 @@ func equipDialog() error {
 		}
 		item = items[id]
 	}
-	// Equip/unequip item.
-	if activeGame.ActivePlayer().Equipment().Equiped(item) {
-		activeGame.ActivePlayer().Unequip(item)
-	} else {
-		err := activeGame.ActivePlayer().Equip(item)
-		if err != nil {
-			msg := lang.Text("equip_error")
-			return fmt.Errorf("%s: %s", msg, err)
-		}
+	return equip(item)
+}
+
+// equip equips specified item for the active player, or
+// unequips it if the item is already equiped.
+func equip(it item.Equiper) error {
+	if actingPlayer().Equipment().Equiped(it) {
+		actingPlayer().Unequip(it)
+		return nil
+	}
+	err := actingPlayer().Equip(it)
+	if err != nil {
+		msg := lang.Text("equip_error")
+		return fmt.Errorf("%s: %s", msg, err)
 	}
 	return nil
 }
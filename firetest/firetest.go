@@ -0,0 +1,236 @@
+/*
+ * firetest.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+// Package with fake Fire server for tests and offline
+// development.
+// Server speaks the Fire protocol over websocket, responses
+// for received requests are specified by the handler function.
+package firetest
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/isangeles/fire/request"
+	"github.com/isangeles/fire/response"
+)
+
+// Function for handling requests received by the server.
+// Returned responses are sent back to the client, in order.
+type HandlerFunc func(req request.Request) []response.Response
+
+// Struct for fake Fire server.
+type Server struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	handler  HandlerFunc
+	requests []request.Request
+	received chan struct{}
+	conns    []*websocket.Conn
+}
+
+// NewServer creates and starts new fake Fire server.
+// By default server sends one empty response for each
+// received request.
+func NewServer() *Server {
+	s := Server{
+		handler:  EmptyResponse,
+		received: make(chan struct{}),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return &s
+}
+
+// EmptyResponse is a handler function that responds with
+// one empty response for each request.
+func EmptyResponse(req request.Request) []response.Response {
+	return []response.Response{{}}
+}
+
+// Host returns server host.
+func (s *Server) Host() string {
+	host, _, _ := net.SplitHostPort(strings.TrimPrefix(s.URL, "http://"))
+	return host
+}
+
+// Port returns server port.
+func (s *Server) Port() string {
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(s.URL, "http://"))
+	return port
+}
+
+// SetHandler sets function for handling received requests.
+// Handler is called from the connection goroutine, for each
+// received request, with the server mutex held, so handlers
+// of all connections are called one at a time and must not
+// call the server methods.
+func (s *Server) SetHandler(f HandlerFunc) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.handler = f
+}
+
+// Respond sets handler that responds with specified responses
+// to the next requests, one response for each request.
+// After all specified responses are sent, server responds
+// with empty responses.
+// Responses queue is guarded by the server mutex held during
+// the handler call.
+func (s *Server) Respond(resps ...response.Response) {
+	queue := make([]response.Response, len(resps))
+	copy(queue, resps)
+	s.SetHandler(func(req request.Request) []response.Response {
+		if len(queue) < 1 {
+			return EmptyResponse(req)
+		}
+		resp := queue[0]
+		queue = queue[1:]
+		return []response.Response{resp}
+	})
+}
+
+// Push sends specified response to all connected clients,
+// without request.
+func (s *Server) Push(resp response.Response) error {
+	text, err := response.Marshal(&resp)
+	if err != nil {
+		return fmt.Errorf("unable to marshal response: %v", err)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, c := range s.conns {
+		err := c.WriteMessage(websocket.TextMessage, []byte(text))
+		if err != nil {
+			return fmt.Errorf("unable to write response: %v", err)
+		}
+	}
+	return nil
+}
+
+// Requests returns all requests received by the server.
+func (s *Server) Requests() []request.Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]request.Request{}, s.requests...)
+}
+
+// WaitRequest waits for the request that matches specified
+// function.
+// Requests received before the call are checked too.
+// Returns error if there was no matching request before
+// specified timeout.
+func (s *Server) WaitRequest(timeout time.Duration, match func(req request.Request) bool) (request.Request, error) {
+	deadline := time.After(timeout)
+	checked := 0
+	for {
+		s.mutex.Lock()
+		requests := s.requests[checked:]
+		received := s.received
+		s.mutex.Unlock()
+		for _, r := range requests {
+			if match(r) {
+				return r, nil
+			}
+		}
+		checked += len(requests)
+		select {
+		case <-received:
+		case <-deadline:
+			return request.Request{}, fmt.Errorf("no matching request after %s", timeout)
+		}
+	}
+}
+
+// DropConnections closes all client connections, without
+// stopping the server.
+func (s *Server) DropConnections() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, c := range s.conns {
+		c.Close()
+	}
+	s.conns = nil
+}
+
+// removeConn closes and removes specified client connection.
+func (s *Server) removeConn(conn *websocket.Conn) {
+	conn.Close()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i, c := range s.conns {
+		if c == conn {
+			s.conns = append(s.conns[:i], s.conns[i+1:]...)
+			return
+		}
+	}
+}
+
+// serve handles client connection.
+// Each received request is passed to the handler function
+// and returned responses are written back to the client.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	s.mutex.Lock()
+	s.conns = append(s.conns, conn)
+	s.mutex.Unlock()
+	defer s.removeConn(conn)
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		req, err := request.Unmarshal(string(msg))
+		if err != nil {
+			continue
+		}
+		s.mutex.Lock()
+		s.requests = append(s.requests, req)
+		close(s.received)
+		s.received = make(chan struct{})
+		resps := s.handler(req)
+		s.mutex.Unlock()
+		for _, resp := range resps {
+			text, err := response.Marshal(&resp)
+			if err != nil {
+				continue
+			}
+			s.mutex.Lock()
+			err = conn.WriteMessage(websocket.TextMessage, []byte(text))
+			s.mutex.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * firetest_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package firetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/isangeles/fire/request"
+	"github.com/isangeles/fire/response"
+)
+
+// TestServerRespond tests responding to the requests with
+// specified responses.
+func TestServerRespond(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.Respond(response.Response{Error: []string{"error_test"}})
+	url := "ws://" + server.Listener.Addr().String() + "/"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Unable to connect to the server: %v", err)
+	}
+	defer conn.Close()
+	// Send.
+	for _, cmd := range []string{"cmd_a", "cmd_b"} {
+		req := request.Request{Command: []string{cmd}}
+		text, err := request.Marshal(&req)
+		if err != nil {
+			t.Fatalf("Unable to marshal request: %v", err)
+		}
+		err = conn.WriteMessage(websocket.TextMessage, []byte(text))
+		if err != nil {
+			t.Fatalf("Unable to write request: %v", err)
+		}
+	}
+	// Test.
+	for i, exp := range []int{1, 0} {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("Unable to read response: %v", err)
+		}
+		resp, err := response.Unmarshal(string(msg))
+		if err != nil {
+			t.Fatalf("Unable to unmarshal response: %v", err)
+		}
+		if len(resp.Error) != exp {
+			t.Errorf("Response %d errors invalid: %d != %d", i, len(resp.Error), exp)
+		}
+	}
+	_, err = server.WaitRequest(time.Second, func(r request.Request) bool {
+		return len(r.Command) > 0 && r.Command[0] == "cmd_b"
+	})
+	if err != nil {
+		t.Fatalf("No request received: %v", err)
+	}
+	if len(server.Requests()) != 2 {
+		t.Errorf("Received requests invalid: %d != 2", len(server.Requests()))
+	}
+}
@@ -0,0 +1,307 @@
+/*
+ * dial.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/isangeles/burnsh/log"
+)
+
+// Struct for server connection options.
+type ServerOptions struct {
+	// Enables TLS(wss://) for the connection.
+	TLS bool
+	// TLS configuration, default configuration is used if nil.
+	TLSConfig *tls.Config
+	// URL path of the server endpoint.
+	Path string
+	// Additional HTTP headers sent with the handshake request,
+	// e.g. auth token.
+	Header http.Header
+	// URL of HTTP, HTTPS or SOCKS5 proxy, proxy from the
+	// standard environment variables is used if empty,
+	// ProxyDirect disables proxy.
+	Proxy string
+	// TLS configuration for the connection to HTTPS proxy,
+	// default configuration is used if nil.
+	ProxyTLSConfig *tls.Config
+	// Maximal time to establish the connection, default
+	// timeout is used if zero.
+	ConnectTimeout time.Duration
+}
+
+// Proxy value that disables proxy for the connection.
+const ProxyDirect = "direct"
+
+// Default maximal time to establish the connection.
+const defaultConnectTimeout = 15 * time.Second
+
+// Struct for TLS configuration options.
+type TLSOptions struct {
+	// Path to the PEM file with CA certificates used to verify
+	// the server certificate, system CA pool is used if empty.
+	CAFile string
+	// Paths to the PEM files with client certificate and key,
+	// for servers that require client authentication.
+	CertFile string
+	KeyFile  string
+	// Server name used to verify the server certificate,
+	// host name is used if empty.
+	ServerName string
+	// Disables verification of the server certificate,
+	// only for development servers.
+	Insecure bool
+}
+
+// NewTLSConfig creates TLS configuration with specified options.
+func NewTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	conf := tls.Config{
+		ServerName:         opts.ServerName,
+		InsecureSkipVerify: opts.Insecure,
+	}
+	if opts.Insecure {
+		log.Err.Printf("Server: TLS certificate verification disabled")
+	}
+	if len(opts.CAFile) > 0 {
+		data, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA file: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no valid certificates in CA file: %s",
+				opts.CAFile)
+		}
+		conf.RootCAs = pool
+	}
+	if len(opts.CertFile+opts.KeyFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %v", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+	return &conf, nil
+}
+
+// serverURL returns URL of the server with specified host and
+// port, for specified options.
+func serverURL(host, port string, opts ServerOptions) string {
+	u := url.URL{
+		Scheme: "ws",
+		Host:   net.JoinHostPort(host, port),
+		Path:   "/" + strings.TrimPrefix(opts.Path, "/"),
+	}
+	if opts.TLS {
+		u.Scheme = "wss"
+	}
+	return u.String()
+}
+
+// dialer returns websocket dialer for specified options.
+func dialer(opts ServerOptions) (*websocket.Dialer, error) {
+	d := *websocket.DefaultDialer
+	d.TLSClientConfig = opts.TLSConfig
+	d.HandshakeTimeout = opts.ConnectTimeout
+	if d.HandshakeTimeout <= 0 {
+		d.HandshakeTimeout = defaultConnectTimeout
+	}
+	proxy, err := proxyFunc(opts.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	if proxy == nil {
+		return &d, nil
+	}
+	// Websocket dialer supports only HTTP and SOCKS5 proxies,
+	// connections through HTTPS proxies are tunneled by the
+	// net dial function, and look like direct connections
+	// for the dialer.
+	scheme := "http"
+	if opts.TLS {
+		scheme = "https"
+	}
+	d.Proxy = func(req *http.Request) (*url.URL, error) {
+		u, err := proxy(req)
+		if u != nil && u.Scheme == "https" {
+			return nil, err
+		}
+		return u, err
+	}
+	d.NetDialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		req := http.Request{URL: &url.URL{Scheme: scheme, Host: addr}}
+		u, err := proxy(&req)
+		if err != nil {
+			return nil, err
+		}
+		if u == nil || u.Scheme != "https" {
+			var nd net.Dialer
+			return nd.DialContext(ctx, network, addr)
+		}
+		return dialTLSProxy(ctx, u, opts.ProxyTLSConfig, network, addr)
+	}
+	return &d, nil
+}
+
+// dialTLSProxy connects to specified address through HTTPS proxy
+// with specified URL, connection to the proxy is secured with TLS
+// with specified configuration.
+func dialTLSProxy(ctx context.Context, proxy *url.URL, conf *tls.Config,
+	network, addr string) (net.Conn, error) {
+	if conf == nil {
+		conf = new(tls.Config)
+	}
+	conf = conf.Clone()
+	if len(conf.ServerName) < 1 {
+		conf.ServerName = proxy.Hostname()
+	}
+	proxyAddr := proxy.Host
+	if len(proxy.Port()) < 1 {
+		proxyAddr = net.JoinHostPort(proxy.Hostname(), "443")
+	}
+	d := tls.Dialer{Config: conf}
+	conn, err := d.DialContext(ctx, network, proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to dial proxy: %v", err)
+	}
+	header := make(http.Header)
+	if proxy.User != nil {
+		pass, _ := proxy.User.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(proxy.User.Username() + ":" + pass))
+		header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+	req := http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: header,
+	}
+	err = req.Write(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to send proxy request: %v", err)
+	}
+	// Buffered reader can be dropped, since the server doesn't
+	// send anything before the handshake request.
+	resp, err := http.ReadResponse(bufio.NewReader(conn), &req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to read proxy response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy connection refused: %s", resp.Status)
+	}
+	return conn, nil
+}
+
+// proxyFunc returns proxy function for the dialer, for specified
+// proxy URL.
+// For empty URL, proxy is taken from HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY environment variables, or from ALL_PROXY if none of
+// them is set.
+func proxyFunc(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	switch proxy {
+	case ProxyDirect:
+		return nil, nil
+	case "":
+		return environmentProxy, nil
+	}
+	u, err := parseProxy(proxy)
+	if err != nil {
+		return nil, err
+	}
+	return http.ProxyURL(u), nil
+}
+
+// environmentProxy returns proxy URL for specified request from the
+// standard proxy environment variables.
+func environmentProxy(req *http.Request) (*url.URL, error) {
+	u, err := http.ProxyFromEnvironment(req)
+	if u != nil || err != nil {
+		return u, err
+	}
+	all := os.Getenv("ALL_PROXY")
+	if len(all) < 1 {
+		all = os.Getenv("all_proxy")
+	}
+	if len(all) < 1 {
+		return nil, nil
+	}
+	return parseProxy(all)
+}
+
+// parseProxy parses specified proxy URL.
+// Only HTTP, HTTPS and SOCKS5 proxies are supported.
+func parseProxy(proxy string) (*url.URL, error) {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %v", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return u, nil
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme: %s", u.Scheme)
+	}
+}
+
+// explainDialError returns error with explanation of specified
+// dial error, if the error was caused by the failed
+// verification of the server certificate.
+func explainDialError(err error) error {
+	var authErr x509.UnknownAuthorityError
+	var hostErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var verifyErr *tls.CertificateVerificationError
+	switch {
+	case errors.As(err, &authErr):
+		return fmt.Errorf("%v: server certificate is signed by unknown authority, "+
+			"specify the CA certificate with the server-ca config value", err)
+	case errors.As(err, &hostErr):
+		return fmt.Errorf("%v: server certificate is not valid for the server host, "+
+			"specify the certificate name with the server-name config value", err)
+	case errors.As(err, &invalidErr):
+		return fmt.Errorf("%v: server certificate is invalid, e.g. expired", err)
+	case errors.As(err, &verifyErr):
+		return fmt.Errorf("%v: unable to verify server certificate", err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,322 @@
+/*
+ * fire_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/area"
+	"github.com/isangeles/flame/character"
+	"github.com/isangeles/flame/data/res"
+
+	"github.com/isangeles/fire/request"
+	"github.com/isangeles/fire/response"
+
+	"github.com/isangeles/burnsh/firetest"
+)
+
+// Maximal time to wait for the fake server in tests.
+const fireTestTimeout = 5 * time.Second
+
+// newFireTest creates fake Fire server and game server
+// connection to it.
+func newFireTest(t *testing.T) (*firetest.Server, *Server) {
+	fire := firetest.NewServer()
+	t.Cleanup(fire.Close)
+	server, err := NewServer(fire.Host(), fire.Port(), ServerOptions{})
+	if err != nil {
+		t.Fatalf("Unable to connect to the fake server: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return fire, server
+}
+
+// newFireTestModule creates module with test area and
+// characters with specified IDs.
+func newFireTestModule(ids ...string) *flame.Module {
+	mod := flame.NewModule(res.ModuleData{})
+	a := area.New(res.AreaData{ID: "area_test"})
+	mod.Chapter().AddAreas(a)
+	for _, id := range ids {
+		a.AddObject(character.New(res.CharacterData{ID: id, Level: 1}))
+	}
+	return mod
+}
+
+// updateHandler returns handler that responds with update
+// of specified module to each request.
+func updateHandler(mod *flame.Module) firetest.HandlerFunc {
+	return func(req request.Request) []response.Response {
+		resp := response.Response{Update: response.Update{Module: mod.Data()}}
+		return []response.Response{resp}
+	}
+}
+
+// waitGame waits until specified condition is met for
+// specified game.
+// Game is locked while checking the condition.
+func waitGame(t *testing.T, g *Game, cond func() bool) {
+	timeout := time.After(fireTestTimeout)
+	for {
+		g.Lock()
+		met := cond()
+		g.Unlock()
+		if met {
+			return
+		}
+		select {
+		case <-timeout:
+			t.Fatalf("Game condition not met in time")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// TestFireLogin tests login to the Fire server.
+func TestFireLogin(t *testing.T) {
+	fire, server := newFireTest(t)
+	req := request.Request{Login: []request.Login{{ID: "user_test", Pass: "pass_test"}}}
+	err := server.Send(req)
+	if err != nil {
+		t.Fatalf("Unable to send login request: %v", err)
+	}
+	// Test.
+	login, err := fire.WaitRequest(fireTestTimeout, func(r request.Request) bool {
+		return len(r.Login) > 0
+	})
+	if err != nil {
+		t.Fatalf("No login request: %v", err)
+	}
+	if login.Login[0].ID != "user_test" || login.Login[0].Pass != "pass_test" {
+		t.Errorf("Login request invalid: %v", login.Login[0])
+	}
+}
+
+// TestFireNewChar tests creating new character on the
+// Fire server.
+func TestFireNewChar(t *testing.T) {
+	fire, server := newFireTest(t)
+	fireMod := newFireTestModule()
+	fire.SetHandler(func(req request.Request) []response.Response {
+		resp := response.Response{}
+		if len(req.NewChar) > 0 {
+			char := character.New(res.CharacterData{ID: "player_test", Level: 1})
+			fireMod.Chapter().Area("area_test").AddObject(char)
+			resp.Character = append(resp.Character, response.Character{
+				ID:     char.ID(),
+				Serial: char.Serial(),
+			})
+		}
+		resp.Update = response.Update{Module: fireMod.Data()}
+		return []response.Response{resp}
+	})
+	g := New(newFireTestModule())
+	g.SetServer(server)
+	charData := res.CharacterData{ID: "player_test", Level: 1}
+	req := request.Request{NewChar: []request.NewChar{{"player_test", charData}}}
+	err := server.Send(req)
+	if err != nil {
+		t.Fatalf("Unable to send new character request: %v", err)
+	}
+	// Test.
+	waitGame(t, g, func() bool { return len(g.players) > 0 })
+	if g.Players()[0].ID() != "player_test" {
+		t.Errorf("New player invalid: %s != player_test", g.Players()[0].ID())
+	}
+}
+
+// TestFireUpdate tests applying update from the Fire server.
+func TestFireUpdate(t *testing.T) {
+	fire, server := newFireTest(t)
+	fireMod := newFireTestModule("char_test")
+	fireChar := fireMod.Chapter().Characters()[0]
+	fireChar.SetPosition(10, 20)
+	fire.SetHandler(updateHandler(fireMod))
+	g := New(newFireTestModule())
+	g.SetServer(server)
+	// Test.
+	waitGame(t, g, func() bool {
+		return g.Chapter().Character(fireChar.ID(), fireChar.Serial()) != nil
+	})
+	g.Lock()
+	defer g.Unlock()
+	x, y := g.Chapter().Character(fireChar.ID(), fireChar.Serial()).Position()
+	if x != 10 || y != 20 {
+		t.Errorf("Updated position invalid: %fx%f != 10x20", x, y)
+	}
+}
+
+// TestFireLoad tests loading saved game on the Fire server.
+func TestFireLoad(t *testing.T) {
+	fire, server := newFireTest(t)
+	fireMod := newFireTestModule("char_test")
+	fire.SetHandler(func(req request.Request) []response.Response {
+		resp := response.Response{Update: response.Update{Module: fireMod.Data()}}
+		if len(req.Load) > 0 {
+			resp.Load = response.Load{Save: req.Load, Module: fireMod.Data()}
+		}
+		return []response.Response{resp}
+	})
+	mod := newFireTestModule("player_test")
+	g := New(mod)
+	g.AddPlayer(NewPlayer(mod.Chapter().Characters()[0], g))
+	g.SetServer(server)
+	err := server.Send(request.Request{Load: "save_test"})
+	if err != nil {
+		t.Fatalf("Unable to send load request: %v", err)
+	}
+	// Test.
+	load, err := fire.WaitRequest(fireTestTimeout, func(r request.Request) bool {
+		return len(r.Load) > 0
+	})
+	if err != nil {
+		t.Fatalf("No load request: %v", err)
+	}
+	if load.Load != "save_test" {
+		t.Errorf("Load request invalid: %s != save_test", load.Load)
+	}
+	waitGame(t, g, func() bool { return len(g.players) < 1 })
+}
+
+// TestFireSave tests saving game on the Fire server.
+func TestFireSave(t *testing.T) {
+	fire, server := newFireTest(t)
+	fire.SetHandler(func(req request.Request) []response.Response {
+		resp := response.Response{}
+		for _, s := range req.Save {
+			if s != "save_test" {
+				resp.Error = append(resp.Error, "invalid save name")
+			}
+		}
+		return []response.Response{resp}
+	})
+	responses := make(chan response.Response, 10)
+	server.SetOnResponseFunc(func(resp response.Response) {
+		responses <- resp
+	})
+	// Test.
+	err := server.Send(request.Request{Save: []string{"save_test"}})
+	if err != nil {
+		t.Fatalf("Unable to send save request: %v", err)
+	}
+	err = server.Send(request.Request{Save: []string{"save_invalid"}})
+	if err != nil {
+		t.Fatalf("Unable to send save request: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case resp := <-responses:
+			if (i == 1) != (len(resp.Error) > 0) {
+				t.Errorf("Save response errors invalid: %d: %v", i, resp.Error)
+			}
+		case <-time.After(fireTestTimeout):
+			t.Fatalf("Save responses received: %d != 2", i)
+		}
+	}
+}
+
+// TestFireTrade tests trade rejected by the Fire server.
+func TestFireTrade(t *testing.T) {
+	fire, server := newFireTest(t)
+	fire.SetHandler(func(req request.Request) []response.Response {
+		resp := response.Response{}
+		if len(req.Trade) > 0 {
+			resp.Error = []string{"trade rejected"}
+		}
+		return []response.Response{resp}
+	})
+	mod := newFireTestModule("player_test", "trader_test")
+	g := New(mod)
+	player := NewPlayer(mod.Chapter().Characters()[0], g)
+	trader := mod.Chapter().Characters()[1]
+	g.AddPlayer(player)
+	g.SetServer(server)
+	g.Trade(trader, player, nil, nil)
+	// Test.
+	trade, err := fire.WaitRequest(fireTestTimeout, func(r request.Request) bool {
+		return len(r.Trade) > 0
+	})
+	if err != nil {
+		t.Fatalf("No trade request: %v", err)
+	}
+	sell := trade.Trade[0].Sell
+	if sell.ObjectFromID != player.ID() || sell.ObjectToID != trader.ID() {
+		t.Errorf("Trade request invalid: %s -> %s", sell.ObjectFromID, sell.ObjectToID)
+	}
+}
+
+// TestFireDialog tests dialog requests sent to the Fire server.
+func TestFireDialog(t *testing.T) {
+	fire, server := newFireTest(t)
+	fire.SetHandler(func(req request.Request) []response.Response {
+		resp := response.Response{}
+		if len(req.DialogAnswer) > 0 {
+			resp.Error = []string{"invalid answer"}
+		}
+		return []response.Response{resp}
+	})
+	responses := make(chan response.Response, 10)
+	server.SetOnResponseFunc(func(resp response.Response) {
+		responses <- resp
+	})
+	dialogReq := request.Dialog{
+		TargetID:     "player_test",
+		TargetSerial: "0",
+		OwnerID:      "npc_test",
+		OwnerSerial:  "0",
+		DialogID:     "dialog_test",
+	}
+	err := server.Send(request.Request{Dialog: []request.Dialog{dialogReq}})
+	if err != nil {
+		t.Fatalf("Unable to send dialog request: %v", err)
+	}
+	answerReq := request.DialogAnswer{Dialog: dialogReq, AnswerID: "answer_test"}
+	err = server.Send(request.Request{DialogAnswer: []request.DialogAnswer{answerReq}})
+	if err != nil {
+		t.Fatalf("Unable to send dialog answer request: %v", err)
+	}
+	// Test.
+	answer, err := fire.WaitRequest(fireTestTimeout, func(r request.Request) bool {
+		return len(r.DialogAnswer) > 0
+	})
+	if err != nil {
+		t.Fatalf("No dialog answer request: %v", err)
+	}
+	if answer.DialogAnswer[0].Dialog.DialogID != "dialog_test" ||
+		answer.DialogAnswer[0].AnswerID != "answer_test" {
+		t.Errorf("Dialog answer request invalid: %v", answer.DialogAnswer[0])
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case resp := <-responses:
+			if (i == 1) != (len(resp.Error) > 0) {
+				t.Errorf("Dialog response errors invalid: %d: %v", i, resp.Error)
+			}
+		case <-time.After(fireTestTimeout):
+			t.Fatalf("Dialog responses received: %d != 2", i)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+/*
+ * formation.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"fmt"
+	"math"
+)
+
+// Type for party formation.
+type Formation string
+
+const (
+	// No formation, party members don't follow
+	// the active player.
+	FormationNone Formation = "none"
+	// Party members stand abreast of the active player.
+	FormationLine Formation = "line"
+	// Party members walk in file behind the active player.
+	FormationColumn Formation = "column"
+	// Party members walk in a V shape behind the active player.
+	FormationWedge Formation = "wedge"
+)
+
+// Default distance between party members in formation.
+const DefaultFormationSpacing = 30.0
+
+// Struct for point of party member in the formation.
+type formationSlot struct {
+	x, y float64
+}
+
+// ParseFormation returns formation with specified name.
+// Empty name stands for no formation.
+func ParseFormation(name string) (Formation, error) {
+	switch f := Formation(name); f {
+	case "":
+		return FormationNone, nil
+	case FormationNone, FormationLine, FormationColumn, FormationWedge:
+		return f, nil
+	default:
+		return FormationNone, fmt.Errorf("unknown formation: %s", name)
+	}
+}
+
+// Formation returns current party formation.
+func (g *Game) Formation() Formation {
+	if len(g.formation) < 1 {
+		return FormationNone
+	}
+	return g.formation
+}
+
+// SetFormation sets party formation and distance between
+// party members in formation.
+// Party members follow the active player in the formation,
+// unless the formation is FormationNone.
+// Default spacing is used if specified spacing is not positive.
+func (g *Game) SetFormation(f Formation, spacing float64) {
+	g.Lock()
+	defer g.Unlock()
+	if spacing <= 0 {
+		spacing = DefaultFormationSpacing
+	}
+	g.formation = f
+	g.formationSpacing = spacing
+	g.formationSlots = make(map[*Player]formationSlot)
+}
+
+// FormationSpacing returns distance between party members
+// in formation.
+func (g *Game) FormationSpacing() float64 {
+	if g.formationSpacing <= 0 {
+		return DefaultFormationSpacing
+	}
+	return g.formationSpacing
+}
+
+// updateFormation sets destination points of party members
+// to their places in the formation around destination point
+// of the active player.
+// Formation faces the direction of the active player movement.
+// Destination of party member is changed only when its place
+// in the formation changes, so party members moved on their
+// own stay in place until the active player moves.
+// Only live party members in the area of the active player
+// follow the formation.
+// Returns moves of party members to send to the server.
+// Game state should be locked by the caller.
+func (g *Game) updateFormation() []playerMove {
+	moves := make([]playerMove, 0)
+	leader := g.activePlayer
+	if g.Formation() == FormationNone || leader == nil {
+		return moves
+	}
+	area := g.Chapter().ObjectArea(leader)
+	if area == nil {
+		return moves
+	}
+	posX, posY := leader.Position()
+	destX, destY := leader.DestPoint()
+	if dist := math.Hypot(destX-posX, destY-posY); dist > 0 {
+		g.formationDirX, g.formationDirY = (destX-posX)/dist, (destY-posY)/dist
+	}
+	if g.formationDirX == 0 && g.formationDirY == 0 {
+		g.formationDirY = 1
+	}
+	if g.formationSlots == nil {
+		g.formationSlots = make(map[*Player]formationSlot)
+	}
+	index := 0
+	for _, pc := range g.players {
+		if pc == leader {
+			continue
+		}
+		if !pc.Live() || g.Chapter().ObjectArea(pc) != area {
+			delete(g.formationSlots, pc)
+			continue
+		}
+		index++
+		offsetX, offsetY := formationOffset(g.Formation(), index, g.FormationSpacing(),
+			g.formationDirX, g.formationDirY)
+		slot := formationSlot{destX + offsetX, destY + offsetY}
+		last, ok := g.formationSlots[pc]
+		if ok && math.Hypot(slot.x-last.x, slot.y-last.y) < g.FormationSpacing()/2 {
+			continue
+		}
+		g.formationSlots[pc] = slot
+		pc.clearRoute()
+		pc.Character.SetDestPoint(slot.x, slot.y)
+		moves = append(moves, playerMove{pc, Point{slot.x, slot.y}})
+	}
+	return moves
+}
+
+// formationOffset returns offset from the leader position for
+// party member with specified index(starting from 1) in specified
+// formation with specified spacing, for formation facing specified
+// direction.
+// Direction is specified as a unit vector.
+func formationOffset(f Formation, index int, spacing, dirX, dirY float64) (float64, float64) {
+	// Members are placed alternately on the right and left side.
+	rank := float64((index + 1) / 2)
+	side := 1.0
+	if index%2 == 0 {
+		side = -1
+	}
+	// Forward and sideways distance from the leader.
+	forward, sideways := 0.0, 0.0
+	switch f {
+	case FormationLine:
+		sideways = side * rank * spacing
+	case FormationColumn:
+		forward = -float64(index) * spacing
+	case FormationWedge:
+		forward = -rank * spacing
+		sideways = side * rank * spacing
+	}
+	// Right vector is the direction rotated by 90 degrees.
+	rightX, rightY := -dirY, dirX
+	return dirX*forward + rightX*sideways, dirY*forward + rightY*sideways
+}
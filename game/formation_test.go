@@ -0,0 +1,80 @@
+/*
+ * formation_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+// TestFormationOffset tests offsets of party members
+// in formations.
+func TestFormationOffset(t *testing.T) {
+	cases := []struct {
+		formation Formation
+		index     int
+		x, y      float64
+	}{
+		{FormationLine, 1, -10, 0},
+		{FormationLine, 2, 10, 0},
+		{FormationLine, 3, -20, 0},
+		{FormationColumn, 1, 0, -10},
+		{FormationColumn, 2, 0, -20},
+		{FormationWedge, 1, -10, -10},
+		{FormationWedge, 2, 10, -10},
+		{FormationWedge, 3, -20, -20},
+		{FormationNone, 1, 0, 0},
+	}
+	for _, c := range cases {
+		x, y := formationOffset(c.formation, c.index, 10, 0, 1)
+		if math.Abs(x-c.x) > 1e-9 || math.Abs(y-c.y) > 1e-9 {
+			t.Errorf("%s offset %d invalid: %fx%f != %fx%f", c.formation,
+				c.index, x, y, c.x, c.y)
+		}
+	}
+	// Formation facing right.
+	x, y := formationOffset(FormationColumn, 1, 10, 1, 0)
+	if math.Abs(x+10) > 1e-9 || math.Abs(y) > 1e-9 {
+		t.Errorf("Rotated offset invalid: %fx%f != -10x0", x, y)
+	}
+}
+
+// TestParseFormation tests parsing formation names.
+func TestParseFormation(t *testing.T) {
+	f, err := ParseFormation("wedge")
+	if err != nil {
+		t.Fatalf("Unable to parse formation: %v", err)
+	}
+	if f != FormationWedge {
+		t.Errorf("Parsed formation invalid: %s != %s", f, FormationWedge)
+	}
+	f, err = ParseFormation("")
+	if err != nil || f != FormationNone {
+		t.Errorf("Empty formation invalid: %s, %v", f, err)
+	}
+	_, err = ParseFormation("circle")
+	if err == nil {
+		t.Errorf("No error for unknown formation")
+	}
+}
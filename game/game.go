@@ -1,7 +1,7 @@
 /*
  * game.go
  *
- * Copyright 2020-2023 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2020-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -26,6 +26,8 @@ package game
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/isangeles/flame"
 	"github.com/isangeles/flame/dialog"
@@ -44,13 +46,25 @@ const (
 )
 
 // Struct for game wrapper.
+// Game mutex synchronises game updates with handling
+// of the server responses.
 type Game struct {
 	*flame.Module
-	server       *Server
-	players      []*Player
-	activePlayer *Player
-	localAI      *ai.AI
-	onLoginFunc  func(g *Game)
+	mutex         sync.Mutex
+	server        *Server
+	players       []*Player
+	activePlayer  *Player
+	localAI       *ai.AI
+	onLoginFunc   func(g *Game)
+	onArrivalFunc func(p *Player, err error)
+	// Party formation state.
+	formation        Formation
+	formationSpacing float64
+	formationDirX    float64
+	formationDirY    float64
+	formationSlots   map[*Player]formationSlot
+	// Local changes waiting for server confirmation.
+	unconfirmed []*trackedChanges
 }
 
 // New creates new game wrapper for specified module.
@@ -61,33 +75,75 @@ func New(module *flame.Module) *Game {
 }
 
 // Update updates game.
+// Game state is locked during the update, requests to the
+// server and arrival functions are triggered after unlocking.
 func (g *Game) Update(delta int64) {
+	g.Lock()
 	g.Module.Update(delta)
-	if g.Server() != nil {
-		return
+	moves, arrivals := g.updateRoutes()
+	moves = append(moves, g.updateFormation()...)
+	g.expireChanges(time.Now())
+	if g.Server() == nil {
+		g.updateAIChars()
+		g.localAI.Update(delta)
+	}
+	g.Unlock()
+	for _, m := range moves {
+		m.player.sendMove(m.dest.X, m.dest.Y)
+	}
+	if g.onArrivalFunc != nil {
+		for _, a := range arrivals {
+			g.onArrivalFunc(a.player, a.err)
+		}
 	}
-	g.updateAIChars()
-	g.localAI.Update(delta)
+}
+
+// Lock locks the game state.
+// Game state is locked during game updates and handling
+// of the server responses.
+func (g *Game) Lock() {
+	g.mutex.Lock()
+}
+
+// Unlock unlocks the game state.
+func (g *Game) Unlock() {
+	g.mutex.Unlock()
 }
 
 // Players returns player characters.
 func (g *Game) Players() []*Player {
-	return g.players
+	g.Lock()
+	defer g.Unlock()
+	return append([]*Player{}, g.players...)
 }
 
 // AddPlayer adds new player character.
 func (g *Game) AddPlayer(player *Player) {
+	g.Lock()
+	defer g.Unlock()
+	g.addPlayer(player)
+}
+
+// addPlayer adds new player character and sets it as
+// active player.
+// Game state should be locked by the caller.
+func (g *Game) addPlayer(player *Player) {
 	g.players = append(g.players, player)
-	g.SetActivePlayer(player)
+	g.activePlayer = player
 }
 
 // ActivePlayer returns active player.
 func (g *Game) ActivePlayer() *Player {
+	g.Lock()
+	defer g.Unlock()
 	return g.activePlayer
 }
 
 // SetActivePlayer sets specified player as active player.
+// Party formation follows the active player.
 func (g *Game) SetActivePlayer(player *Player) {
+	g.Lock()
+	defer g.Unlock()
 	g.activePlayer = player
 }
 
@@ -112,9 +168,20 @@ func (g *Game) SetOnLoginFunc(f func(g *Game)) {
 	g.onLoginFunc = f
 }
 
+// SetOnArrivalFunc sets function triggered when player ends
+// the route started with MoveTo, with ErrRouteStuck as an
+// error if the player stopped moving before the destination.
+// Function is triggered during the game update, after
+// unlocking the game state.
+func (g *Game) SetOnArrivalFunc(f func(p *Player, err error)) {
+	g.onArrivalFunc = f
+}
+
 // SpawnPlayer places specified player in the area and on the position specified in
 // game module configuration.
 func (g *Game) SpawnPlayer(player *Player) error {
+	g.Lock()
+	defer g.Unlock()
 	// Set start position.
 	player.SetPosition(g.Chapter().Conf().StartPosX, g.Chapter().Conf().StartPosY)
 	// Set start area.
@@ -129,15 +196,23 @@ func (g *Game) SpawnPlayer(player *Player) error {
 
 // TransferItems transfer items between specified objects.
 // Items are in the form of a map with IDs as keys and serial values as values.
+// Returns an error if any of the items was not found.
+// Transfer is reverted if the server rejects it.
 func (g *Game) TransferItems(from, to item.Container, items ...item.Item) error {
+	g.Lock()
+	changes := make([]localChange, 0)
 	for _, i := range items {
 		if from.Inventory().Item(i.ID(), i.Serial()) == nil {
+			undoChanges(changes)
+			g.Unlock()
 			return fmt.Errorf("Item not found: %s %s",
 				i.ID(), i.Serial())
 		}
 		from.Inventory().RemoveItem(i)
 		to.Inventory().AddItem(i)
+		changes = append(changes, transferChange(from, to, i))
 	}
+	g.Unlock()
 	if g.Server() == nil {
 		return nil
 	}
@@ -152,7 +227,7 @@ func (g *Game) TransferItems(from, to item.Container, items ...item.Item) error
 		transferReq.Items[i.ID()] = append(transferReq.Items[i.ID()], i.Serial())
 	}
 	req := request.Request{TransferItems: []request.TransferItems{transferReq}}
-	err := g.Server().Send(req)
+	err := g.sendChanges(req, changes...)
 	if err != nil {
 		log.Err.Printf("Game: transfer items: unable to send transfer items request: %v",
 			err)
@@ -161,15 +236,21 @@ func (g *Game) TransferItems(from, to item.Container, items ...item.Item) error
 }
 
 // Trade exchanges items between specified containers.
+// Trade is reverted if the server rejects it.
 func (g *Game) Trade(seller, buyer item.Container, sellItems, buyItems []item.Item) {
+	g.Lock()
+	changes := make([]localChange, 0)
 	for _, it := range sellItems {
 		buyer.Inventory().RemoveItem(it)
 		seller.Inventory().AddItem(it)
+		changes = append(changes, transferChange(buyer, seller, it))
 	}
 	for _, it := range buyItems {
 		seller.Inventory().RemoveItem(it)
 		buyer.Inventory().AddItem(it)
+		changes = append(changes, transferChange(seller, buyer, it))
 	}
+	g.Unlock()
 	if g.Server() == nil {
 		return
 	}
@@ -195,7 +276,7 @@ func (g *Game) Trade(seller, buyer item.Container, sellItems, buyItems []item.It
 	}
 	tradeReq := request.Trade{Sell: transferReqSell, Buy: transferReqBuy}
 	req := request.Request{Trade: []request.Trade{tradeReq}}
-	err := g.Server().Send(req)
+	err := g.sendChanges(req, changes...)
 	if err != nil {
 		log.Err.Printf("Game: trade items: unable to send trade request: %v",
 			err)
@@ -204,8 +285,10 @@ func (g *Game) Trade(seller, buyer item.Container, sellItems, buyItems []item.It
 
 // StartDialog starts dialog with specified object as dialog target.
 func (g *Game) StartDialog(dialog *dialog.Dialog, target dialog.Talker) {
+	g.Lock()
 	dialog.Restart()
 	dialog.SetTarget(target)
+	g.Unlock()
 	if g.Server() == nil || dialog.Owner() == nil {
 		return
 	}
@@ -226,7 +309,9 @@ func (g *Game) StartDialog(dialog *dialog.Dialog, target dialog.Talker) {
 
 // AnswerDialog answers dialog with specified answer.
 func (g *Game) AnswerDialog(dialog *dialog.Dialog, answer *dialog.Answer) {
+	g.Lock()
 	dialog.Next(answer)
+	g.Unlock()
 	if g.Server() == nil || dialog.Owner() == nil || dialog.Target() == nil {
 		return
 	}
@@ -1,7 +1,7 @@
 /*
  * player.go
  *
- * Copyright 2020-2022 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2020-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -42,8 +42,9 @@ import (
 // Wrapper struct for player character.
 type Player struct {
 	*character.Character
-	game *Game
-	log  *objects.Log
+	game  *Game
+	log   *objects.Log
+	route route
 }
 
 // NewPlayer creates new game player.
@@ -63,15 +64,28 @@ func (p *Player) Log() *objects.Log {
 
 // SetPosition sets a specified XY position as a
 // player position and destination point.
+// Current route of the player is removed.
+// Game state should be locked by the caller.
 func (p *Player) SetPosition(x, y float64) {
 	p.Character.SetPosition(x, y)
-	p.SetDestPoint(x, y)
+	p.clearRoute()
+	p.Character.SetDestPoint(x, y)
 }
 
 // SetDestPoint sets a specified XY position as a
 // character destination point.
+// Current route of the player is removed.
 func (p *Player) SetDestPoint(x, y float64) {
+	p.game.Lock()
+	p.clearRoute()
 	p.Character.SetDestPoint(x, y)
+	p.game.Unlock()
+	p.sendMove(x, y)
+}
+
+// sendMove sends move request to specified XY position
+// to the server.
+func (p *Player) sendMove(x, y float64) {
 	if p.game.Server() == nil {
 		return
 	}
@@ -86,7 +100,9 @@ func (p *Player) SetDestPoint(x, y float64) {
 
 // AddChatMessage adds new message to player chat log.
 func (p *Player) AddChatMessage(message string) {
+	p.game.Lock()
 	p.ChatLog().Add(objects.NewMessage(message, true))
+	p.game.Unlock()
 	if p.game.Server() == nil {
 		return
 	}
@@ -101,7 +117,9 @@ func (p *Player) AddChatMessage(message string) {
 
 // SetTarget sets specified targetable object as current target.
 func (p *Player) SetTarget(tar effect.Target) {
+	p.game.Lock()
 	p.Character.SetTarget(tar)
+	p.game.Unlock()
 	if p.game.Server() == nil {
 		return
 	}
@@ -122,7 +140,9 @@ func (p *Player) SetTarget(tar effect.Target) {
 
 // Use uses specified usable object.
 func (p *Player) Use(ob useaction.Usable) {
+	p.game.Lock()
 	err := p.Character.Use(ob)
+	p.game.Unlock()
 	if err != nil {
 		p.Log().Add(objects.Message{Text: "cant_do_right_now"})
 		return
@@ -148,31 +168,14 @@ func (p *Player) Use(ob useaction.Usable) {
 
 // Equip inserts specified equipable item to all
 // compatible slots in active PC equipment.
+// Returns an error if the item can't be equiped.
+// Item is unequiped if the server rejects the change.
 func (p *Player) Equip(it item.Equiper) error {
-	if !p.MeetReqs(it.EquipReqs()...) {
-		return fmt.Errorf(lang.Text("reqs_not_meet"))
-	}
-	slots := make([]*character.EquipmentSlot, 0)
-	for _, itSlot := range it.Slots() {
-		equiped := false
-		for _, eqSlot := range p.Equipment().Slots() {
-			if eqSlot.Item() != nil {
-				continue
-			}
-			if eqSlot.Type() == itSlot {
-				eqSlot.SetItem(it)
-				equiped = true
-				slots = append(slots, eqSlot)
-				break
-			}
-		}
-		if !equiped {
-			p.Equipment().Unequip(it)
-			return fmt.Errorf(lang.Text("equip_no_free_slot_error"))
-		}
-	}
-	if !p.Equipment().Equiped(it) {
-		return fmt.Errorf(lang.Text("equip_no_valid_slot_error"))
+	p.game.Lock()
+	slots, err := p.equipSlots(it)
+	p.game.Unlock()
+	if err != nil {
+		return err
 	}
 	if p.game.Server() == nil {
 		return nil
@@ -191,7 +194,7 @@ func (p *Player) Equip(it item.Equiper) error {
 		eqReq.Slots = append(eqReq.Slots, slotReq)
 	}
 	req := request.Request{Equip: []request.Equip{eqReq}}
-	err := p.game.Server().Send(req)
+	err = p.game.sendChanges(req, equipChange(p.Equipment(), it))
 	if err != nil {
 		log.Err.Printf("Player: %s %s: unable to send equip request: %v",
 			p.ID(), p.Serial(), err)
@@ -199,9 +202,53 @@ func (p *Player) Equip(it item.Equiper) error {
 	return nil
 }
 
+// equipSlots inserts specified equipable item to all
+// compatible slots in player equipment.
+// Returns slots with the item, or an error if the item
+// can't be equiped.
+// Game state should be locked by the caller.
+func (p *Player) equipSlots(it item.Equiper) ([]*character.EquipmentSlot, error) {
+	if !p.MeetReqs(it.EquipReqs()...) {
+		return nil, fmt.Errorf(lang.Text("reqs_not_meet"))
+	}
+	slots := make([]*character.EquipmentSlot, 0)
+	for _, itSlot := range it.Slots() {
+		equiped := false
+		for _, eqSlot := range p.Equipment().Slots() {
+			if eqSlot.Item() != nil {
+				continue
+			}
+			if eqSlot.Type() == itSlot {
+				eqSlot.SetItem(it)
+				equiped = true
+				slots = append(slots, eqSlot)
+				break
+			}
+		}
+		if !equiped {
+			p.Equipment().Unequip(it)
+			return nil, fmt.Errorf(lang.Text("equip_no_free_slot_error"))
+		}
+	}
+	if !p.Equipment().Equiped(it) {
+		return nil, fmt.Errorf(lang.Text("equip_no_valid_slot_error"))
+	}
+	return slots, nil
+}
+
 // Unequip removes specified item from player equipment.
+// Item is equiped back if the server rejects the change.
 func (p *Player) Unequip(it item.Equiper) {
+	p.game.Lock()
+	slots := make([]*character.EquipmentSlot, 0)
+	for _, s := range p.Equipment().Slots() {
+		if s.Item() != nil && s.Item().ID() == it.ID() &&
+			s.Item().Serial() == it.Serial() {
+			slots = append(slots, s)
+		}
+	}
 	p.Equipment().Unequip(it)
+	p.game.Unlock()
 	if p.game.Server() == nil {
 		return
 	}
@@ -212,7 +259,7 @@ func (p *Player) Unequip(it item.Equiper) {
 		ItemSerial: it.Serial(),
 	}
 	req := request.Request{Unequip: []request.Unequip{uneqReq}}
-	err := p.game.Server().Send(req)
+	err := p.game.sendChanges(req, unequipChange(p.Equipment(), it, slots))
 	if err != nil {
 		log.Err.Printf("Player: %s %s: unable to send unequip request: %v",
 			p.ID(), p.Serial(), err)
@@ -1,7 +1,7 @@
 /*
  * response.go
  *
- * Copyright 2020-2021 Dariusz Sikora <dev@isangeles.pl>
+ * Copyright 2020-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -38,10 +38,12 @@ import (
 var addPlayerMutex sync.Mutex
 
 // handleResponse handles specified response from Fire server.
+// Game state is locked while handling the response.
 func (g *Game) handleResponse(resp response.Response) {
 	if !resp.Logon && g.onLoginFunc != nil {
 		g.onLoginFunc(g)
 	}
+	g.Lock()
 	if len(resp.Load.Save) > 0 {
 		g.handleLoadResponse(resp.Load)
 	}
@@ -52,13 +54,15 @@ func (g *Game) handleResponse(resp response.Response) {
 	for _, r := range resp.Error {
 		log.Err.Printf("Game server error: %s", r)
 	}
+	g.handleChanges(resp)
+	g.Unlock()
 }
 
 // handleCharacterResponse handles new characters from server response.
 func (g *Game) handleCharacterResponse(resp response.Character) {
 	addPlayerMutex.Lock()
 	defer addPlayerMutex.Unlock()
-	for _, p := range g.Players() {
+	for _, p := range g.players {
 		if p.ID() == resp.ID && p.Serial() == resp.Serial {
 			return
 		}
@@ -70,7 +74,7 @@ func (g *Game) handleCharacterResponse(resp response.Character) {
 		return
 	}
 	player := NewPlayer(char, g)
-	g.AddPlayer(player)
+	g.addPlayer(player)
 }
 
 // handleUpdateRespone handles update response.
@@ -84,6 +88,7 @@ func (g *Game) handleUpdateResponse(resp response.Update) {
 func (g *Game) handleLoadResponse(resp response.Load) {
 	serial.Reset()
 	g.players = make([]*Player, 0)
-	g.SetActivePlayer(nil)
+	g.activePlayer = nil
+	g.formationSlots = nil
 	g.Apply(resp.Module)
 }
@@ -0,0 +1,297 @@
+/*
+ * rollback.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/isangeles/flame/character"
+	"github.com/isangeles/flame/item"
+
+	"github.com/isangeles/fire/request"
+	"github.com/isangeles/fire/response"
+
+	"github.com/isangeles/burnsh/log"
+)
+
+// Maximal time to wait for the server game state confirming
+// local changes.
+var confirmTimeout = 10 * time.Second
+
+// Struct for local change applied to the game before
+// confirmation from the server.
+type localChange struct {
+	// Reverts the change, does nothing if the change
+	// is already reverted.
+	undo func()
+	// Checks if the change is present in the game state.
+	holds func() bool
+	// Checks if specified server error is about the change.
+	rejectedBy func(err string) bool
+}
+
+// Struct for local changes waiting for confirmation
+// in the game state from the server.
+type trackedChanges struct {
+	changes  []localChange
+	deadline time.Time
+	// Set if the game state from the server was applied
+	// since the request was sent.
+	updated bool
+}
+
+// sendChanges sends specified request to the server and tracks
+// specified local changes applied to the game for the request.
+// Returns an error if the request was not sent.
+// Changes are reverted if the request was not sent, or if the
+// server rejects the changes or they are not present in the game
+// state from the server in time, unless the game state from the
+// server was applied since the request was sent.
+// Game state must not be locked by the caller, changes are tracked
+// and reverted with the game state locked.
+func (g *Game) sendChanges(req request.Request, changes ...localChange) error {
+	if len(changes) < 1 {
+		return g.Server().Send(req)
+	}
+	t := g.trackChanges(changes...)
+	err := g.Server().Send(req)
+	if err != nil {
+		g.Lock()
+		g.untrackChanges(t)
+		undoChanges(changes)
+		g.Unlock()
+		return err
+	}
+	return nil
+}
+
+// trackChanges adds specified changes to the changes waiting for
+// confirmation.
+// Returns tracked changes.
+func (g *Game) trackChanges(changes ...localChange) *trackedChanges {
+	t := trackedChanges{
+		changes:  changes,
+		deadline: time.Now().Add(confirmTimeout),
+	}
+	g.Lock()
+	defer g.Unlock()
+	g.unconfirmed = append(g.unconfirmed, &t)
+	return &t
+}
+
+// untrackChanges removes specified tracked changes.
+// Game state should be locked by the caller.
+func (g *Game) untrackChanges(t *trackedChanges) {
+	for i, u := range g.unconfirmed {
+		if u == t {
+			g.unconfirmed = append(g.unconfirmed[:i], g.unconfirmed[i+1:]...)
+			return
+		}
+	}
+}
+
+// handleChanges checks tracked changes after specified server
+// response was applied to the game.
+// Game state should be locked by the caller.
+func (g *Game) handleChanges(resp response.Response) {
+	updated := !reflect.ValueOf(resp.Update).IsZero()
+	g.confirmChanges(updated)
+	g.rejectChanges(resp.Error)
+}
+
+// confirmChanges stops tracking changes present in the game
+// state, if the game state was updated by the server.
+// Changes missing in the game state are not reverted, since the
+// server could push the update before handling the request, but
+// are marked as updated, so they are never reverted over the
+// game state from the server.
+// Game state should be locked by the caller.
+func (g *Game) confirmChanges(updated bool) {
+	if !updated {
+		return
+	}
+	unconfirmed := make([]*trackedChanges, 0)
+	for _, t := range g.unconfirmed {
+		if changesHold(t.changes) {
+			continue
+		}
+		t.updated = true
+		unconfirmed = append(unconfirmed, t)
+	}
+	g.unconfirmed = unconfirmed
+}
+
+// rejectChanges stops tracking changes rejected by specified
+// server errors and reverts them, unless the game state from
+// the server was applied since the request was sent.
+// Fire errors are not labeled with request IDs, so an error
+// rejects only changes of objects named in the error.
+// Game state should be locked by the caller.
+func (g *Game) rejectChanges(errs []string) {
+	if len(errs) < 1 {
+		return
+	}
+	unconfirmed := make([]*trackedChanges, 0)
+	for _, t := range g.unconfirmed {
+		err, rejected := changesRejected(t.changes, errs)
+		if !rejected {
+			unconfirmed = append(unconfirmed, t)
+			continue
+		}
+		if t.updated {
+			log.Err.Printf("Game: local changes rejected by the server: %s", err)
+			continue
+		}
+		log.Err.Printf("Game: local changes rejected by the server, reverting: %s", err)
+		undoChanges(t.changes)
+	}
+	g.unconfirmed = unconfirmed
+}
+
+// expireChanges stops tracking changes not confirmed before
+// specified time and reverts them, unless the game state from
+// the server was applied since the request was sent.
+// Game state should be locked by the caller.
+func (g *Game) expireChanges(now time.Time) {
+	unconfirmed := make([]*trackedChanges, 0)
+	for _, t := range g.unconfirmed {
+		if now.Before(t.deadline) {
+			unconfirmed = append(unconfirmed, t)
+			continue
+		}
+		if t.updated {
+			log.Err.Printf("Game: local changes not confirmed by the server")
+			continue
+		}
+		log.Err.Printf("Game: no game state from the server, reverting local changes")
+		undoChanges(t.changes)
+	}
+	g.unconfirmed = unconfirmed
+}
+
+// changesRejected checks if any of specified server errors
+// is about any of specified changes.
+// Returns the first error about the changes.
+func changesRejected(changes []localChange, errs []string) (string, bool) {
+	for _, err := range errs {
+		for _, c := range changes {
+			if c.rejectedBy != nil && c.rejectedBy(err) {
+				return err, true
+			}
+		}
+	}
+	return "", false
+}
+
+// changesHold checks if all specified changes are present
+// in the game state.
+func changesHold(changes []localChange) bool {
+	for _, c := range changes {
+		if !c.holds() {
+			return false
+		}
+	}
+	return true
+}
+
+// undoChanges reverts specified changes in reverse order.
+func undoChanges(changes []localChange) {
+	for i := len(changes) - 1; i >= 0; i-- {
+		changes[i].undo()
+	}
+}
+
+// transferChange returns local change for transfer of specified
+// item between specified containers.
+// Item is moved back only if it's still in the destination
+// container.
+func transferChange(from, to item.Container, it item.Item) localChange {
+	c := localChange{
+		undo: func() {
+			if to.Inventory().Item(it.ID(), it.Serial()) == nil {
+				return
+			}
+			to.Inventory().RemoveItem(it)
+			if from.Inventory().Item(it.ID(), it.Serial()) == nil {
+				from.Inventory().AddItem(it)
+			}
+		},
+		holds: func() bool {
+			return to.Inventory().Item(it.ID(), it.Serial()) != nil &&
+				from.Inventory().Item(it.ID(), it.Serial()) == nil
+		},
+		rejectedBy: itemError(it),
+	}
+	return c
+}
+
+// equipChange returns local change for equiping specified item
+// in specified equipment.
+func equipChange(eq *character.Equipment, it item.Equiper) localChange {
+	c := localChange{
+		undo: func() {
+			if eq.Equiped(it) {
+				eq.Unequip(it)
+			}
+		},
+		holds: func() bool {
+			return eq.Equiped(it)
+		},
+		rejectedBy: itemError(it),
+	}
+	return c
+}
+
+// unequipChange returns local change for removing specified item
+// from specified equipment slots.
+func unequipChange(eq *character.Equipment, it item.Equiper,
+	slots []*character.EquipmentSlot) localChange {
+	c := localChange{
+		undo: func() {
+			if eq.Equiped(it) {
+				return
+			}
+			for _, s := range slots {
+				if s.Item() == nil {
+					s.SetItem(it)
+				}
+			}
+		},
+		holds: func() bool {
+			return !eq.Equiped(it)
+		},
+		rejectedBy: itemError(it),
+	}
+	return c
+}
+
+// itemError returns function that checks if server error is
+// about specified item, i.e. contains the item ID and serial.
+func itemError(it item.Item) func(err string) bool {
+	return func(err string) bool {
+		return strings.Contains(err, it.ID()) && strings.Contains(err, it.Serial())
+	}
+}
@@ -0,0 +1,258 @@
+/*
+ * rollback_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/isangeles/flame/character"
+	"github.com/isangeles/flame/data/res"
+	"github.com/isangeles/flame/item"
+
+	"github.com/isangeles/fire/request"
+)
+
+// testChange returns local change with specified name that
+// sets specified value to 1 and reverts it to 0.
+func testChange(value *int32, name string) localChange {
+	atomic.StoreInt32(value, 1)
+	c := localChange{
+		undo:       func() { atomic.StoreInt32(value, 0) },
+		holds:      func() bool { return atomic.LoadInt32(value) == 1 },
+		rejectedBy: func(err string) bool { return strings.Contains(err, name) },
+	}
+	return c
+}
+
+// TestTrackChangesLost tests reverting local changes without
+// game state from the server.
+func TestTrackChangesLost(t *testing.T) {
+	game := Game{}
+	lost := int32(0)
+	game.trackChanges(testChange(&lost, "change_test"))
+	// Test.
+	game.Lock()
+	game.expireChanges(time.Now())
+	game.Unlock()
+	if len(game.unconfirmed) != 1 {
+		t.Errorf("Change expired before deadline")
+	}
+	game.Lock()
+	game.expireChanges(time.Now().Add(confirmTimeout))
+	game.Unlock()
+	if len(game.unconfirmed) > 0 {
+		t.Errorf("Lost change still tracked")
+	}
+	if atomic.LoadInt32(&lost) != 0 {
+		t.Errorf("Lost change not reverted")
+	}
+}
+
+// TestTrackChangesPushed tests confirming local changes with
+// game state pushed by the server before handling the request.
+func TestTrackChangesPushed(t *testing.T) {
+	game := Game{}
+	value := int32(0)
+	game.trackChanges(testChange(&value, "change_test"))
+	// Pushed server state without the change.
+	atomic.StoreInt32(&value, 0)
+	game.Lock()
+	game.confirmChanges(true)
+	game.Unlock()
+	if len(game.unconfirmed) != 1 {
+		t.Errorf("Change confirmed by pushed state")
+	}
+	// Server state with the change.
+	atomic.StoreInt32(&value, 1)
+	game.Lock()
+	game.confirmChanges(true)
+	game.Unlock()
+	if len(game.unconfirmed) > 0 {
+		t.Errorf("Change not confirmed")
+	}
+}
+
+// TestTrackChangesRejected tests reverting only the local
+// changes named in the server error.
+func TestTrackChangesRejected(t *testing.T) {
+	game := Game{}
+	accepted, rejected := int32(0), int32(0)
+	game.trackChanges(testChange(&accepted, "accepted_test"))
+	game.trackChanges(testChange(&rejected, "rejected_test"))
+	// Test.
+	game.Lock()
+	game.rejectChanges([]string{"unable to transfer: rejected_test"})
+	game.Unlock()
+	if atomic.LoadInt32(&rejected) != 0 {
+		t.Errorf("Rejected change not reverted")
+	}
+	if atomic.LoadInt32(&accepted) != 1 {
+		t.Errorf("Accepted change reverted")
+	}
+	if len(game.unconfirmed) != 1 {
+		t.Errorf("Unconfirmed changes invalid: %d != 1", len(game.unconfirmed))
+	}
+}
+
+// TestTrackChangesUpdated tests keeping game state from
+// the server applied after the request was sent.
+func TestTrackChangesUpdated(t *testing.T) {
+	game := Game{}
+	rejected, lost := int32(0), int32(0)
+	game.trackChanges(testChange(&rejected, "rejected_test"))
+	game.trackChanges(testChange(&lost, "lost_test"))
+	// Server state with other values.
+	atomic.StoreInt32(&rejected, 2)
+	atomic.StoreInt32(&lost, 2)
+	game.Lock()
+	game.confirmChanges(true)
+	game.rejectChanges([]string{"rejected_test"})
+	game.expireChanges(time.Now().Add(confirmTimeout))
+	game.Unlock()
+	// Test.
+	if atomic.LoadInt32(&rejected) != 2 || atomic.LoadInt32(&lost) != 2 {
+		t.Errorf("Changes reverted over server state: %d, %d",
+			atomic.LoadInt32(&rejected), atomic.LoadInt32(&lost))
+	}
+	if len(game.unconfirmed) > 0 {
+		t.Errorf("Changes still tracked")
+	}
+}
+
+// TestSendChangesFailed tests reverting local changes of the
+// request not sent to the server.
+func TestSendChangesFailed(t *testing.T) {
+	messages := make(chan string, 10)
+	game := Game{server: newTestServer(t, messagesHandler(t, messages))}
+	game.Server().Close()
+	value := int32(0)
+	err := game.sendChanges(request.Request{}, testChange(&value, "change_test"))
+	if err == nil {
+		t.Errorf("No error for closed server")
+	}
+	if atomic.LoadInt32(&value) != 0 {
+		t.Errorf("Change not reverted")
+	}
+	if len(game.unconfirmed) > 0 {
+		t.Errorf("Failed change still tracked")
+	}
+}
+
+// TestTransferChange tests local change of item transfer.
+func TestTransferChange(t *testing.T) {
+	from := character.New(res.CharacterData{ID: "from_test"})
+	to := character.New(res.CharacterData{ID: "to_test"})
+	other := character.New(res.CharacterData{ID: "other_test"})
+	it := item.NewWeapon(res.WeaponData{ID: "weapon_test"})
+	to.Inventory().AddItem(it)
+	c := transferChange(from, to, it)
+	if !c.holds() {
+		t.Errorf("Transfer change not present")
+	}
+	errMsg := "item not found: " + it.ID() + " " + it.Serial()
+	if !c.rejectedBy(errMsg) || c.rejectedBy("item not found: other") {
+		t.Errorf("Transfer change rejection invalid")
+	}
+	// Test.
+	c.undo()
+	if c.holds() || from.Inventory().Item(it.ID(), it.Serial()) == nil {
+		t.Errorf("Transfer change not reverted")
+	}
+	// Item moved by the server.
+	to.Inventory().AddItem(it)
+	from.Inventory().RemoveItem(it)
+	to.Inventory().RemoveItem(it)
+	other.Inventory().AddItem(it)
+	c.undo()
+	if from.Inventory().Item(it.ID(), it.Serial()) != nil {
+		t.Errorf("Item moved by the server duplicated")
+	}
+}
+
+// TestEquipChange tests local change of item equip.
+func TestEquipChange(t *testing.T) {
+	char := character.New(res.CharacterData{ID: "char_test"})
+	it := item.NewWeapon(res.WeaponData{ID: "weapon_test",
+		Slots: []res.ItemSlotData{{"hand"}}})
+	char.Inventory().AddItem(it)
+	var slot *character.EquipmentSlot
+	for _, s := range char.Equipment().Slots() {
+		if s.Type() == item.Hand {
+			slot = s
+			break
+		}
+	}
+	if slot == nil {
+		t.Fatalf("No hand slot")
+	}
+	slot.SetItem(it)
+	c := equipChange(char.Equipment(), it)
+	if !c.holds() {
+		t.Errorf("Equip change not present")
+	}
+	// Test.
+	c.undo()
+	if c.holds() || char.Equipment().Equiped(it) {
+		t.Errorf("Equip change not reverted")
+	}
+}
+
+// TestUnequipChange tests local change of item unequip.
+func TestUnequipChange(t *testing.T) {
+	char := character.New(res.CharacterData{ID: "char_test"})
+	it := item.NewWeapon(res.WeaponData{ID: "weapon_test",
+		Slots: []res.ItemSlotData{{"hand"}}})
+	char.Inventory().AddItem(it)
+	slots := make([]*character.EquipmentSlot, 0)
+	for _, s := range char.Equipment().Slots() {
+		if s.Type() == item.Hand {
+			slots = append(slots, s)
+			break
+		}
+	}
+	if len(slots) < 1 {
+		t.Fatalf("No hand slot")
+	}
+	c := unequipChange(char.Equipment(), it, slots)
+	if !c.holds() {
+		t.Errorf("Unequip change not present")
+	}
+	// Test.
+	c.undo()
+	if c.holds() || slots[0].Item() != it {
+		t.Errorf("Unequip change not reverted")
+	}
+	// Slot taken by other item.
+	char.Equipment().Unequip(it)
+	other := item.NewWeapon(res.WeaponData{ID: "weapon_other",
+		Slots: []res.ItemSlotData{{"hand"}}})
+	slots[0].SetItem(other)
+	c.undo()
+	if char.Equipment().Equiped(it) || slots[0].Item() != other {
+		t.Errorf("Item equiped in taken slot")
+	}
+}
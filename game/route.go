@@ -0,0 +1,179 @@
+/*
+ * route.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+var ErrRouteStuck = errors.New("route stuck")
+
+const (
+	// Distance from the waypoint at which the waypoint
+	// is reached.
+	routeArrivalRange = 1.0
+	// Maximal time without movement before the route is
+	// abandoned.
+	routeStuckTimeout = 3 * time.Second
+)
+
+// Struct for point on the route.
+type Point struct {
+	X, Y float64
+}
+
+// Struct for route of player character.
+type route struct {
+	mutex    sync.Mutex
+	points   []Point
+	lastX    float64
+	lastY    float64
+	lastMove time.Time
+}
+
+// Struct for player move waiting to be sent to the server.
+type playerMove struct {
+	player *Player
+	dest   Point
+}
+
+// Struct for player that ended the route.
+type routeArrival struct {
+	player *Player
+	err    error
+}
+
+// MoveTo sets route through specified waypoints in the player
+// area and starts moving the player along the route.
+// The player moves in a straight line between waypoints, the
+// route is not checked against area bounds or obstacles, since
+// Flame provides no such area data.
+// Returns an error if the player is not in any area.
+func (p *Player) MoveTo(waypoints ...Point) error {
+	if len(waypoints) < 1 {
+		return fmt.Errorf("no waypoints specified")
+	}
+	p.game.Lock()
+	points, err := p.planRoute(waypoints)
+	p.game.Unlock()
+	if err != nil {
+		return err
+	}
+	p.sendMove(points[0].X, points[0].Y)
+	return nil
+}
+
+// planRoute sets route through specified waypoints in the player
+// area and sets the first point of the route as the player
+// destination point.
+// Returns points of the route.
+// Game state should be locked by the caller.
+func (p *Player) planRoute(waypoints []Point) ([]Point, error) {
+	if p.game.Chapter().ObjectArea(p) == nil {
+		return nil, fmt.Errorf("player area not found")
+	}
+	x, y := p.Position()
+	points := append([]Point{}, waypoints...)
+	p.route.mutex.Lock()
+	defer p.route.mutex.Unlock()
+	p.route.points = points
+	p.route.lastX, p.route.lastY = x, y
+	p.route.lastMove = time.Now()
+	p.Character.SetDestPoint(points[0].X, points[0].Y)
+	return points, nil
+}
+
+// Route returns remaining waypoints of the player route.
+func (p *Player) Route() []Point {
+	p.route.mutex.Lock()
+	defer p.route.mutex.Unlock()
+	return append([]Point{}, p.route.points...)
+}
+
+// clearRoute removes player route.
+func (p *Player) clearRoute() {
+	p.route.mutex.Lock()
+	defer p.route.mutex.Unlock()
+	p.route.points = nil
+}
+
+// updateRoute sets the next waypoint of the route as the player
+// destination point, after reaching the current one.
+// Returns the new destination point to send to the server, and
+// true if the route ended, with ErrRouteStuck if the player
+// stopped moving before reaching the destination.
+// Game state should be locked by the caller.
+func (p *Player) updateRoute() (*Point, bool, error) {
+	p.route.mutex.Lock()
+	defer p.route.mutex.Unlock()
+	if len(p.route.points) < 1 {
+		return nil, false, nil
+	}
+	x, y := p.Position()
+	next := p.route.points[0]
+	if math.Hypot(next.X-x, next.Y-y) <= routeArrivalRange {
+		p.route.points = p.route.points[1:]
+		if len(p.route.points) < 1 {
+			return nil, true, nil
+		}
+		p.route.lastMove = time.Now()
+		dest := p.route.points[0]
+		p.Character.SetDestPoint(dest.X, dest.Y)
+		return &dest, false, nil
+	}
+	if x != p.route.lastX || y != p.route.lastY {
+		p.route.lastX, p.route.lastY = x, y
+		p.route.lastMove = time.Now()
+		return nil, false, nil
+	}
+	if time.Since(p.route.lastMove) < routeStuckTimeout {
+		return nil, false, nil
+	}
+	p.route.points = nil
+	p.Character.SetDestPoint(x, y)
+	return &Point{x, y}, true, ErrRouteStuck
+}
+
+// updateRoutes updates routes of all players.
+// Returns moves to send to the server and players that ended
+// their routes.
+// Game state should be locked by the caller.
+func (g *Game) updateRoutes() ([]playerMove, []routeArrival) {
+	moves := make([]playerMove, 0)
+	arrivals := make([]routeArrival, 0)
+	for _, p := range g.players {
+		dest, done, err := p.updateRoute()
+		if dest != nil {
+			moves = append(moves, playerMove{p, *dest})
+		}
+		if done {
+			arrivals = append(arrivals, routeArrival{p, err})
+		}
+	}
+	return moves, arrivals
+}
@@ -0,0 +1,75 @@
+/*
+ * route_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"testing"
+
+	"github.com/isangeles/flame/character"
+	"github.com/isangeles/flame/data/res"
+)
+
+// TestPlayerMoveTo tests moving player along the route through
+// waypoints in the game area.
+func TestPlayerMoveTo(t *testing.T) {
+	mod := newFireTestModule("player_test")
+	g := New(mod)
+	pc := NewPlayer(mod.Chapter().Characters()[0], g)
+	g.AddPlayer(pc)
+	waypoints := []Point{{10, 20}, {30, 40}}
+	err := pc.MoveTo(waypoints...)
+	if err != nil {
+		t.Fatalf("Unable to move player: %v", err)
+	}
+	if x, y := pc.DestPoint(); x != 10 || y != 20 {
+		t.Errorf("Destination point invalid: %fx%f != 10x20", x, y)
+	}
+	// Test.
+	g.Lock()
+	pc.Character.SetPosition(10, 20)
+	dest, done, err := pc.updateRoute()
+	g.Unlock()
+	if dest == nil || *dest != waypoints[1] || done || err != nil {
+		t.Errorf("Route update invalid: %v %v %v", dest, done, err)
+	}
+	if x, y := pc.DestPoint(); x != 30 || y != 40 {
+		t.Errorf("Destination point invalid: %fx%f != 30x40", x, y)
+	}
+	g.Lock()
+	pc.Character.SetPosition(30, 40)
+	_, done, err = pc.updateRoute()
+	g.Unlock()
+	if !done || err != nil {
+		t.Errorf("Route not ended: %v %v", done, err)
+	}
+	if len(pc.Route()) > 0 {
+		t.Errorf("Route waypoints number invalid: %d != 0", len(pc.Route()))
+	}
+	// Player outside areas.
+	outside := NewPlayer(character.New(res.CharacterData{ID: "player_outside", Level: 1}), g)
+	err = outside.MoveTo(waypoints...)
+	if err == nil {
+		t.Errorf("No error for player outside areas")
+	}
+}
@@ -24,7 +24,16 @@
 package game
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
 
@@ -34,93 +43,622 @@ import (
 	"github.com/isangeles/burnsh/log"
 )
 
+// Type for server connection state.
+type ConnState int
+
+const (
+	Connected ConnState = iota
+	Reconnecting
+	Disconnected
+)
+
+const (
+	// Maximal number of requests waiting in the send queue.
+	queueSize = 64
+	// Maximal time to wait for a place in the send queue.
+	queueTimeout = 5 * time.Second
+	// Maximal time to write a request to the connection.
+	writeTimeout = 10 * time.Second
+	// Time to wait before checking disabled ping again.
+	pingIdleDelay = time.Second
+)
+
+var (
+	ErrQueueFull = errors.New("request queue is full")
+	ErrClosed    = errors.New("server connection closed")
+)
+
+// Struct for server connection statistics.
+// Only data messages are counted, ping and pong control
+// messages are used to measure the latency.
+type NetStats struct {
+	Latency          time.Duration
+	BytesSent        uint64
+	BytesReceived    uint64
+	MessagesSent     uint64
+	MessagesReceived uint64
+}
+
 // Struct for server connection.
+// All requests are written to the connection by a single
+// writer goroutine, from the send queue.
+// Responses are handled in order of arrival, by a single
+// dispatcher goroutine.
+// Fire responses carry no request IDs and the server pushes
+// responses on its own, so responses are not matched with
+// requests.
+// Connection is kept alive with websocket pings, connection
+// without pong from the server in time is treated as lost.
 type Server struct {
-	closed     bool
-	conn       *websocket.Conn
-	onResponse func(r response.Response)
+	mutex         sync.RWMutex
+	url           string
+	dialer        *websocket.Dialer
+	header        http.Header
+	state         ConnState
+	conn          *websocket.Conn
+	login         *request.Login
+	minDelay      time.Duration
+	maxDelay      time.Duration
+	batchInterval time.Duration
+	pingInterval  time.Duration
+	pingTimeout   time.Duration
+	latency       time.Duration
+	bytesSent     atomic.Uint64
+	bytesRecv     atomic.Uint64
+	msgsSent      atomic.Uint64
+	msgsRecv      atomic.Uint64
+	queue         chan *queuedRequest
+	responses     chan response.Response
+	stop          chan struct{}
+	stopOnce      sync.Once
+	recordMutex   sync.Mutex
+	recorder      *json.Encoder
+	recordFile    *os.File
+	replay        *session
+	onResponse    func(r response.Response)
+	onStateChange func(s ConnState)
+}
+
+// Struct for request waiting in the send queue.
+type queuedRequest struct {
+	req    request.Request
+	result chan error
 }
 
 // NewServer creates new server connection struct with connection
 // to the server with specified host and port number.
-func NewServer(host, port string, tls bool) (*Server, error) {
-	s := new(Server)
-	scheme := "ws"
-	if tls {
-		scheme = "wss"
-	}
-	address := fmt.Sprintf("%s://%s:%s/", scheme, host, port)
-	conn, _, err := websocket.DefaultDialer.Dial(address, nil)
+func NewServer(host, port string, opts ServerOptions) (*Server, error) {
+	d, err := dialer(opts)
 	if err != nil {
-		return nil, fmt.Errorf("Unable to dial server: %v", err)
+		return nil, fmt.Errorf("Unable to create dialer: %v", err)
+	}
+	s := Server{
+		url:       serverURL(host, port, opts),
+		dialer:    d,
+		header:    opts.Header,
+		minDelay:  time.Second,
+		maxDelay:  30 * time.Second,
+		queue:     make(chan *queuedRequest, queueSize),
+		responses: make(chan response.Response, queueSize),
+		stop:      make(chan struct{}),
 	}
+	conn, _, err := s.dialer.Dial(s.url, s.header)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to dial server: %v", explainDialError(err))
+	}
+	s.setupConn(conn)
 	s.conn = conn
 	go s.handleResponses()
-	return s, nil
+	go s.dispatchResponses()
+	go s.writeRequests()
+	go s.ping()
+	return &s, nil
+}
+
+// String returns name of the connection state.
+func (cs ConnState) String() string {
+	switch cs {
+	case Connected:
+		return "connected"
+	case Reconnecting:
+		return "reconnecting"
+	case Disconnected:
+		return "disconnected"
+	default:
+		return "unknown"
+	}
 }
 
 // Close closes server connection.
+// Closed connection is not reconnected.
 func (s *Server) Close() error {
-	err := s.conn.Close()
+	s.setState(Disconnected)
+	s.stopOnce.Do(func() { close(s.stop) })
+	s.stopRecording()
+	if s.replay != nil {
+		return nil
+	}
+	err := s.connection().Close()
 	if err != nil {
 		return fmt.Errorf("Unable to close server connection: %v",
 			err)
 	}
-	s.closed = true
 	return nil
 }
 
 // Closed checks if server connection was closed.
 func (s *Server) Closed() bool {
-	return s.closed
+	return s.State() == Disconnected
+}
+
+// State returns current state of the server connection.
+func (s *Server) State() ConnState {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.state
 }
 
 // Address returns server address.
+// For replayed session, returns path to the session file.
 func (s *Server) Address() string {
-	return s.conn.RemoteAddr().String()
+	if s.replay != nil {
+		return s.replay.path
+	}
+	return s.connection().RemoteAddr().String()
 }
 
 // SetOnServerResponseFunc sets function triggered on server reponse.
+// Function is called for each response, in order of arrival,
+// never concurrently.
 func (s *Server) SetOnResponseFunc(f func(r response.Response)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	s.onResponse = f
 }
 
+// SetOnStateChangeFunc sets function triggered on change of
+// the connection state.
+func (s *Server) SetOnStateChangeFunc(f func(cs ConnState)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.onStateChange = f
+}
+
+// SetReconnectDelay sets minimal and maximal delay between
+// reconnect attempts.
+// Delay is doubled after each failed attempt, up to the
+// maximal delay.
+func (s *Server) SetReconnectDelay(min, max time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.minDelay = min
+	s.maxDelay = max
+}
+
+// SetBatchInterval sets interval for batching requests.
+// Requests sent during the interval are merged and written
+// to the server as a single request.
+// Non-positive interval disables batching.
+func (s *Server) SetBatchInterval(interval time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.batchInterval = interval
+}
+
+// SetPing sets interval between pings sent to the server, and
+// maximal time to wait for the pong.
+// Connection without pong in time is treated as lost and
+// reconnected.
+// Non-positive interval disables pings.
+func (s *Server) SetPing(interval, timeout time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.pingInterval = interval
+	s.pingTimeout = timeout
+}
+
+// Stats returns connection statistics.
+func (s *Server) Stats() NetStats {
+	s.mutex.RLock()
+	latency := s.latency
+	s.mutex.RUnlock()
+	stats := NetStats{
+		Latency:          latency,
+		BytesSent:        s.bytesSent.Load(),
+		BytesReceived:    s.bytesRecv.Load(),
+		MessagesSent:     s.msgsSent.Load(),
+		MessagesReceived: s.msgsRecv.Load(),
+	}
+	return stats
+}
+
 // Update sends an empty request to the server to trigger the update response.
 func (s *Server) Update() error {
 	return s.Send(request.Request{})
 }
 
 // Send sends specified request to the server.
+// Request is added to the send queue and written by the
+// writer goroutine, Send blocks until the request is written.
+// Returns ErrQueueFull if there was no place in the send queue
+// for too long, or ErrClosed if the connection was closed.
+// Login from the request is remembered to log in again
+// after reconnect.
 func (s *Server) Send(req request.Request) error {
+	if s.State() != Connected {
+		return fmt.Errorf("Server not connected: %s", s.State())
+	}
+	qr := queuedRequest{req: req, result: make(chan error, 1)}
+	err := s.enqueue(&qr)
+	if err != nil {
+		return err
+	}
+	if len(req.Login) > 0 {
+		s.mutex.Lock()
+		s.login = &req.Login[len(req.Login)-1]
+		s.mutex.Unlock()
+	}
+	return nil
+}
+
+// enqueue adds specified request to the send queue and
+// waits until the request is written.
+func (s *Server) enqueue(qr *queuedRequest) error {
+	timer := time.NewTimer(queueTimeout)
+	defer timer.Stop()
+	select {
+	case s.queue <- qr:
+	case <-s.stop:
+		return ErrClosed
+	case <-timer.C:
+		return ErrQueueFull
+	}
+	select {
+	case err := <-qr.result:
+		return err
+	case <-s.stop:
+		return ErrClosed
+	}
+}
+
+// writeRequests writes requests from the send queue to the
+// server connection, until the connection is closed.
+func (s *Server) writeRequests() {
+	var next *queuedRequest
+	for {
+		batch := make([]*queuedRequest, 0)
+		if next != nil {
+			batch = append(batch, next)
+			next = nil
+		} else {
+			select {
+			case qr := <-s.queue:
+				batch = append(batch, qr)
+			case <-s.stop:
+				return
+			}
+		}
+		batch, next = s.collectBatch(batch)
+		req := request.Request{}
+		for _, qr := range batch {
+			mergeRequests(&req, qr.req)
+		}
+		err := s.write(req)
+		for _, qr := range batch {
+			qr.result <- err
+		}
+	}
+}
+
+// collectBatch adds requests sent during the batch interval
+// to specified batch.
+// Requests with single-value fields are written alone, so
+// batch ends before such request, which is returned as the
+// next request to write.
+func (s *Server) collectBatch(batch []*queuedRequest) ([]*queuedRequest, *queuedRequest) {
+	s.mutex.RLock()
+	interval := s.batchInterval
+	s.mutex.RUnlock()
+	if interval <= 0 || singleValue(batch[0].req) {
+		return batch, nil
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for len(batch) < queueSize {
+		select {
+		case qr := <-s.queue:
+			if singleValue(qr.req) {
+				return batch, qr
+			}
+			batch = append(batch, qr)
+		case <-timer.C:
+			return batch, nil
+		case <-s.stop:
+			return batch, nil
+		}
+	}
+	return batch, nil
+}
+
+// write writes specified request to the server connection.
+// Write fails if the request is not written in time.
+func (s *Server) write(req request.Request) error {
+	if s.State() != Connected {
+		return fmt.Errorf("Server not connected: %s", s.State())
+	}
 	text, err := request.Marshal(&req)
 	if err != nil {
 		return fmt.Errorf("Unable to marshal request: %v", err)
 	}
-	err = s.conn.WriteMessage(websocket.TextMessage, []byte(text))
+	s.record(SessionEntry{Request: text})
+	if s.replay != nil {
+		return nil
+	}
+	conn := s.connection()
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	err = conn.WriteMessage(websocket.TextMessage, []byte(text))
 	if err != nil {
+		// Connection is unusable after failed write, closed
+		// connection is reconnected by the response handler.
+		conn.Close()
 		return fmt.Errorf("Unable to write request: %v", err)
 	}
+	s.msgsSent.Add(1)
+	s.bytesSent.Add(uint64(len(text)))
 	return nil
 }
 
 // handleResponses handles responses from the server and
 // triggers onServerResponse for each response.
+// Reconnects to the server if connection was lost.
 func (s *Server) handleResponses() {
 	for {
-		_, msg, err := s.conn.ReadMessage()
-		if err != nil {
-			log.Err.Printf("Server response: Unable to read from the server: %v",
-				err)
+		err := s.readResponses()
+		if s.Closed() {
+			return
+		}
+		log.Err.Printf("Server response: Unable to read from the server: %v",
+			err)
+		if !s.reconnect() {
 			return
 		}
+	}
+}
+
+// readResponses reads responses from the current connection
+// until read error occurs.
+func (s *Server) readResponses() error {
+	conn := s.connection()
+	for {
+		s.extendReadDeadline(conn)
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		s.msgsRecv.Add(1)
+		s.bytesRecv.Add(uint64(len(msg)))
+		s.record(SessionEntry{Response: string(msg)})
 		resp, err := response.Unmarshal(string(msg))
 		if err != nil {
 			log.Err.Printf("Server response: Unable to unmarshal server response: %v",
 				err)
+			continue
+		}
+		select {
+		case s.responses <- resp:
+		case <-s.stop:
+			return ErrClosed
+		}
+	}
+}
+
+// dispatchResponses triggers response function for each
+// response from the responses queue, until the connection
+// is closed.
+func (s *Server) dispatchResponses() {
+	for {
+		select {
+		case resp := <-s.responses:
+			s.dispatch(resp)
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// dispatch triggers response function for specified response.
+func (s *Server) dispatch(resp response.Response) {
+	s.mutex.RLock()
+	onResponse := s.onResponse
+	s.mutex.RUnlock()
+	if onResponse != nil {
+		onResponse(resp)
+	}
+}
+
+// reconnect tries to reconnect to the server with exponential
+// backoff, until connection is established or closed.
+// After reconnect, last login request is sent again and
+// update is requested to resynchronise the game state.
+// Returns false if connection was closed before reconnect.
+func (s *Server) reconnect() bool {
+	s.setState(Reconnecting)
+	s.mutex.RLock()
+	delay, maxDelay := s.minDelay, s.maxDelay
+	s.mutex.RUnlock()
+	for !s.Closed() {
+		conn, _, err := s.dialer.Dial(s.url, s.header)
+		if err != nil {
+			log.Err.Printf("Server: unable to reconnect: %v", explainDialError(err))
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-s.stop:
+				timer.Stop()
+				return false
+			}
+			delay *= 2
+			if delay > maxDelay {
+				delay = maxDelay
+			}
+			continue
+		}
+		s.mutex.Lock()
+		if s.state == Disconnected {
+			s.mutex.Unlock()
+			conn.Close()
+			return false
+		}
+		s.setupConn(conn)
+		s.conn = conn
+		s.mutex.Unlock()
+		s.setState(Connected)
+		s.resume()
+		return true
+	}
+	return false
+}
+
+// resume sends last login request and update request to
+// the server.
+func (s *Server) resume() {
+	s.mutex.RLock()
+	login := s.login
+	s.mutex.RUnlock()
+	if login != nil {
+		req := request.Request{Login: []request.Login{*login}}
+		err := s.Send(req)
+		if err != nil {
+			log.Err.Printf("Server: unable to send login request: %v", err)
+		}
+	}
+	err := s.Update()
+	if err != nil {
+		log.Err.Printf("Server: unable to send update request: %v", err)
+	}
+}
+
+// ping sends pings to the server in ping intervals, until the
+// connection is closed.
+// Ping payload contains the send time, to measure latency
+// when the pong arrives.
+func (s *Server) ping() {
+	for {
+		s.mutex.RLock()
+		interval, timeout := s.pingInterval, s.pingTimeout
+		s.mutex.RUnlock()
+		wait := interval
+		if wait <= 0 {
+			wait = pingIdleDelay
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-s.stop:
+			timer.Stop()
 			return
 		}
-		if s.onResponse != nil {
-			go s.onResponse(resp)
+		if interval <= 0 || s.State() != Connected {
+			continue
+		}
+		payload := strconv.FormatInt(time.Now().UnixNano(), 10)
+		err := s.connection().WriteControl(websocket.PingMessage, []byte(payload),
+			time.Now().Add(timeout))
+		if err != nil {
+			log.Err.Printf("Server: unable to send ping: %v", err)
+		}
+	}
+}
+
+// setupConn sets pong handler for specified connection.
+// Pong handler updates the latency and extends the read
+// deadline of the connection.
+func (s *Server) setupConn(conn *websocket.Conn) {
+	conn.SetPongHandler(func(data string) error {
+		sent, err := strconv.ParseInt(data, 10, 64)
+		if err == nil {
+			s.mutex.Lock()
+			s.latency = time.Since(time.Unix(0, sent))
+			s.mutex.Unlock()
+		}
+		s.extendReadDeadline(conn)
+		return nil
+	})
+}
+
+// extendReadDeadline sets read deadline for specified connection,
+// so read fails if there is no message or pong from the server
+// until the next ping and the pong timeout.
+// Removes deadline if pings are disabled.
+func (s *Server) extendReadDeadline(conn *websocket.Conn) {
+	s.mutex.RLock()
+	interval, timeout := s.pingInterval, s.pingTimeout
+	s.mutex.RUnlock()
+	if interval <= 0 {
+		conn.SetReadDeadline(time.Time{})
+		return
+	}
+	conn.SetReadDeadline(time.Now().Add(interval + timeout))
+}
+
+// connection returns current server connection.
+func (s *Server) connection() *websocket.Conn {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.conn
+}
+
+// setState sets specified state as connection state and
+// triggers state change function.
+// Closed connection state can't be changed.
+func (s *Server) setState(cs ConnState) {
+	s.mutex.Lock()
+	if s.state == cs || s.state == Disconnected {
+		s.mutex.Unlock()
+		return
+	}
+	s.state = cs
+	onStateChange := s.onStateChange
+	s.mutex.Unlock()
+	if onStateChange != nil {
+		onStateChange(cs)
+	}
+}
+
+// singleValue checks if specified request has any non-zero field
+// that holds a single value, like load or close, and can't be
+// merged with other requests without losing the value.
+func singleValue(req request.Request) bool {
+	val := reflect.ValueOf(req)
+	for i := 0; i < val.NumField(); i++ {
+		field := val.Field(i)
+		if field.Kind() != reflect.Slice && !field.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeRequests merges specified source request into specified
+// destination request.
+// Slice fields of the source request are appended to the
+// destination fields, other non-zero fields replace
+// destination values, so requests with single-value fields
+// should not be merged.
+func mergeRequests(dest *request.Request, src request.Request) {
+	destVal := reflect.ValueOf(dest).Elem()
+	srcVal := reflect.ValueOf(src)
+	for i := 0; i < srcVal.NumField(); i++ {
+		field := srcVal.Field(i)
+		destField := destVal.Field(i)
+		if !destField.CanSet() {
+			continue
+		}
+		switch {
+		case field.Kind() == reflect.Slice:
+			destField.Set(reflect.AppendSlice(destField, field))
+		case !field.IsZero():
+			destField.Set(field)
 		}
 	}
 }
@@ -0,0 +1,476 @@
+/*
+ * server_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/isangeles/fire/request"
+	"github.com/isangeles/fire/response"
+)
+
+// newTestServer creates test HTTP server with specified
+// handler and returns game server connection to it.
+func newTestServer(t *testing.T, handler http.HandlerFunc) *Server {
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(httpServer.Close)
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(httpServer.URL, "http://"))
+	if err != nil {
+		t.Fatalf("Unable to split server address: %v", err)
+	}
+	server, err := NewServer(host, port, ServerOptions{})
+	if err != nil {
+		t.Fatalf("Unable to connect to the server: %v", err)
+	}
+	return server
+}
+
+// messagesHandler returns handler for test HTTP server that
+// sends all received messages to specified channel.
+func messagesHandler(t *testing.T, messages chan string) http.HandlerFunc {
+	upgrader := websocket.Upgrader{}
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Unable to upgrade connection: %v", err)
+			return
+		}
+		defer conn.Close()
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			messages <- string(msg)
+		}
+	}
+}
+
+// TestServerReconnect tests reconnecting to the server
+// after the connection was lost.
+func TestServerReconnect(t *testing.T) {
+	// Create server.
+	messages := make(chan string, 10)
+	conns := int32(0)
+	upgrader := websocket.Upgrader{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Unable to upgrade connection: %v", err)
+			return
+		}
+		defer conn.Close()
+		first := atomic.AddInt32(&conns, 1) == 1
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if first {
+				// Drop the first connection after login.
+				return
+			}
+			messages <- string(msg)
+		}
+	}
+	server := newTestServer(t, handler)
+	defer server.Close()
+	server.SetReconnectDelay(10*time.Millisecond, time.Second)
+	states := make(chan ConnState, 10)
+	server.SetOnStateChangeFunc(func(cs ConnState) { states <- cs })
+	req := request.Request{Login: []request.Login{{ID: "user_test", Pass: "pass_test"}}}
+	err := server.Send(req)
+	if err != nil {
+		t.Fatalf("Unable to send login request: %v", err)
+	}
+	// Test.
+	for _, exp := range []ConnState{Reconnecting, Connected} {
+		select {
+		case cs := <-states:
+			if cs != exp {
+				t.Errorf("Connection state invalid: %s != %s", cs, exp)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Connection state not changed to: %s", exp)
+		}
+	}
+	select {
+	case msg := <-messages:
+		if !strings.Contains(msg, "user_test") {
+			t.Errorf("No login request after reconnect: %s", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("No request after reconnect")
+	}
+}
+
+// TestServerSendConcurrent tests sending requests from many
+// goroutines.
+func TestServerSendConcurrent(t *testing.T) {
+	messages := make(chan string, 100)
+	server := newTestServer(t, messagesHandler(t, messages))
+	defer server.Close()
+	// Send.
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := request.Request{Command: []string{fmt.Sprintf("cmd%d", i)}}
+			err := server.Send(req)
+			if err != nil {
+				t.Errorf("Unable to send request: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	// Test.
+	for i := 0; i < 50; i++ {
+		select {
+		case <-messages:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Requests received: %d != 50", i)
+		}
+	}
+}
+
+// TestServerSendBatch tests batching requests.
+func TestServerSendBatch(t *testing.T) {
+	messages := make(chan string, 10)
+	server := newTestServer(t, messagesHandler(t, messages))
+	defer server.Close()
+	server.SetBatchInterval(100 * time.Millisecond)
+	// Send.
+	var wg sync.WaitGroup
+	for _, cmd := range []string{"cmd_a", "cmd_b", "cmd_c"} {
+		wg.Add(1)
+		go func(cmd string) {
+			defer wg.Done()
+			err := server.Send(request.Request{Command: []string{cmd}})
+			if err != nil {
+				t.Errorf("Unable to send request: %v", err)
+			}
+		}(cmd)
+	}
+	wg.Wait()
+	// Test.
+	select {
+	case msg := <-messages:
+		for _, cmd := range []string{"cmd_a", "cmd_b", "cmd_c"} {
+			if !strings.Contains(msg, cmd) {
+				t.Errorf("Batched request without command: %s: %s", cmd, msg)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("No batched request received")
+	}
+	select {
+	case msg := <-messages:
+		t.Errorf("Request not batched: %s", msg)
+	default:
+	}
+}
+
+// TestServerSendBatchSingle tests writing requests with
+// single-value fields outside batches.
+func TestServerSendBatchSingle(t *testing.T) {
+	messages := make(chan string, 10)
+	server := newTestServer(t, messagesHandler(t, messages))
+	defer server.Close()
+	server.SetBatchInterval(100 * time.Millisecond)
+	// Send.
+	reqs := []request.Request{
+		{Command: []string{"cmd_test"}},
+		{Load: "save_a"},
+		{Load: "save_b"},
+	}
+	var wg sync.WaitGroup
+	for _, req := range reqs {
+		wg.Add(1)
+		go func(req request.Request) {
+			defer wg.Done()
+			err := server.Send(req)
+			if err != nil {
+				t.Errorf("Unable to send request: %v", err)
+			}
+		}(req)
+	}
+	wg.Wait()
+	// Test.
+	loads := 0
+	for loads < 2 {
+		select {
+		case msg := <-messages:
+			if strings.Contains(msg, "save_a") && strings.Contains(msg, "save_b") {
+				t.Errorf("Load requests merged: %s", msg)
+			}
+			if strings.Contains(msg, "save_a") || strings.Contains(msg, "save_b") {
+				loads++
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Load requests received: %d != 2", loads)
+		}
+	}
+}
+
+// TestServerSendClosed tests sending request to the
+// closed server.
+func TestServerSendClosed(t *testing.T) {
+	messages := make(chan string, 10)
+	server := newTestServer(t, messagesHandler(t, messages))
+	server.Close()
+	err := server.Send(request.Request{})
+	if err == nil {
+		t.Errorf("No error for closed server")
+	}
+}
+
+// TestServerResponsesOrder tests handling server responses
+// in order of arrival.
+func TestServerResponsesOrder(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Unable to upgrade connection: %v", err)
+			return
+		}
+		defer conn.Close()
+		// Wait for the first request.
+		_, _, err = conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		for i := 0; i < 20; i++ {
+			resp := response.Response{Error: []string{fmt.Sprintf("%d", i)}}
+			text, err := response.Marshal(&resp)
+			if err != nil {
+				t.Errorf("Unable to marshal response: %v", err)
+				return
+			}
+			conn.WriteMessage(websocket.TextMessage, []byte(text))
+		}
+		conn.ReadMessage()
+	}
+	server := newTestServer(t, handler)
+	defer server.Close()
+	// Handle responses.
+	handled := make(chan string, 20)
+	active := int32(0)
+	server.SetOnResponseFunc(func(resp response.Response) {
+		if atomic.AddInt32(&active, 1) > 1 {
+			t.Errorf("Responses handled concurrently")
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&active, -1)
+		for _, e := range resp.Error {
+			handled <- e
+		}
+	})
+	err := server.Update()
+	if err != nil {
+		t.Fatalf("Unable to send update request: %v", err)
+	}
+	// Test.
+	for i := 0; i < 20; i++ {
+		select {
+		case e := <-handled:
+			if e != fmt.Sprintf("%d", i) {
+				t.Errorf("Response order invalid: %s != %d", e, i)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Responses handled: %d != 20", i)
+		}
+	}
+}
+
+// TestServerPing tests measuring latency with pings and
+// counting sent messages.
+func TestServerPing(t *testing.T) {
+	messages := make(chan string, 10)
+	server := newTestServer(t, messagesHandler(t, messages))
+	defer server.Close()
+	server.SetPing(10*time.Millisecond, time.Second)
+	err := server.Send(request.Request{Command: []string{"cmd_test"}})
+	if err != nil {
+		t.Fatalf("Unable to send request: %v", err)
+	}
+	// Test.
+	timeout := time.After(5 * time.Second)
+	for server.Stats().Latency <= 0 {
+		select {
+		case <-timeout:
+			t.Fatalf("No latency measured")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	stats := server.Stats()
+	if stats.MessagesSent != 1 {
+		t.Errorf("Sent messages invalid: %d != 1", stats.MessagesSent)
+	}
+	msg := <-messages
+	if stats.BytesSent != uint64(len(msg)) {
+		t.Errorf("Sent bytes invalid: %d != %d", stats.BytesSent, len(msg))
+	}
+}
+
+// TestServerTLS tests connecting to the server with custom CA.
+func TestServerTLS(t *testing.T) {
+	// Create server.
+	messages := make(chan string, 10)
+	httpServer := httptest.NewTLSServer(messagesHandler(t, messages))
+	defer httpServer.Close()
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(httpServer.URL, "https://"))
+	if err != nil {
+		t.Fatalf("Unable to split server address: %v", err)
+	}
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE",
+		Bytes: httpServer.Certificate().Raw})
+	err = os.WriteFile(caFile, caData, 0644)
+	if err != nil {
+		t.Fatalf("Unable to write CA file: %v", err)
+	}
+	// Test.
+	_, err = NewServer(host, port, ServerOptions{TLS: true})
+	if err == nil || !strings.Contains(err.Error(), "server-ca") {
+		t.Errorf("No certificate error explanation: %v", err)
+	}
+	tlsConf, err := NewTLSConfig(TLSOptions{CAFile: caFile, ServerName: "example.com"})
+	if err != nil {
+		t.Fatalf("Unable to create TLS config: %v", err)
+	}
+	server, err := NewServer(host, port, ServerOptions{TLS: true, TLSConfig: tlsConf})
+	if err != nil {
+		t.Fatalf("Unable to connect to the server: %v", err)
+	}
+	server.Close()
+}
+
+// TestServerHTTPSProxy tests connecting to the server
+// through HTTPS proxy.
+func TestServerHTTPSProxy(t *testing.T) {
+	// Create servers.
+	messages := make(chan string, 10)
+	httpServer := httptest.NewServer(messagesHandler(t, messages))
+	defer httpServer.Close()
+	connects := make(chan string, 1)
+	proxy := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		connects <- r.Host
+		dest, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer dest.Close()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Unable to hijack proxy connection: %v", err)
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("HTTP/1.1 200 OK\r\n\r\n"))
+		go io.Copy(dest, conn)
+		io.Copy(conn, dest)
+	}))
+	defer proxy.Close()
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(httpServer.URL, "http://"))
+	if err != nil {
+		t.Fatalf("Unable to split server address: %v", err)
+	}
+	opts := ServerOptions{
+		Proxy:          proxy.URL,
+		ProxyTLSConfig: proxy.Client().Transport.(*http.Transport).TLSClientConfig,
+	}
+	// Test.
+	server, err := NewServer(host, port, opts)
+	if err != nil {
+		t.Fatalf("Unable to connect to the server: %v", err)
+	}
+	defer server.Close()
+	addr := <-connects
+	if addr != net.JoinHostPort(host, port) {
+		t.Errorf("Proxy connect address invalid: %s != %s", addr,
+			net.JoinHostPort(host, port))
+	}
+}
+
+// TestServerPathHeader tests connecting to the server endpoint
+// with custom path and headers.
+func TestServerPathHeader(t *testing.T) {
+	// Create server.
+	requests := make(chan *http.Request, 1)
+	upgrader := websocket.Upgrader{}
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Unable to upgrade connection: %v", err)
+			return
+		}
+		defer conn.Close()
+		conn.ReadMessage()
+	}))
+	defer httpServer.Close()
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(httpServer.URL, "http://"))
+	if err != nil {
+		t.Fatalf("Unable to split server address: %v", err)
+	}
+	opts := ServerOptions{
+		Path:   "fire/game",
+		Header: http.Header{"Authorization": []string{"Bearer token_test"}},
+		Proxy:  ProxyDirect,
+	}
+	server, err := NewServer(host, port, opts)
+	if err != nil {
+		t.Fatalf("Unable to connect to the server: %v", err)
+	}
+	defer server.Close()
+	// Test.
+	r := <-requests
+	if r.URL.Path != "/fire/game" {
+		t.Errorf("Request path invalid: %s != /fire/game", r.URL.Path)
+	}
+	if h := r.Header.Get("Authorization"); h != "Bearer token_test" {
+		t.Errorf("Request header invalid: %s != Bearer token_test", h)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+ * session.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/isangeles/fire/response"
+
+	"github.com/isangeles/burnsh/log"
+)
+
+// Struct for entry of the recorded server session.
+// Entry contains request sent to the server or response
+// received from the server, in form of Fire protocol text.
+type SessionEntry struct {
+	Time     time.Time `json:"time"`
+	Request  string    `json:"request,omitempty"`
+	Response string    `json:"response,omitempty"`
+}
+
+// Struct for recorded session replayed by the server.
+type session struct {
+	path    string
+	entries []SessionEntry
+}
+
+// ReadSession reads session recorded in file with specified path.
+// Session file contains one JSON entry per line.
+func ReadSession(path string) ([]SessionEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open session file: %v", err)
+	}
+	defer file.Close()
+	entries := make([]SessionEntry, 0)
+	scan := bufio.NewScanner(file)
+	scan.Buffer(nil, 64*1024*1024)
+	for line := 1; scan.Scan(); line++ {
+		if len(scan.Bytes()) < 1 {
+			continue
+		}
+		var e SessionEntry
+		err := json.Unmarshal(scan.Bytes(), &e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid session entry at line %d: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scan.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read session file: %v", err)
+	}
+	return entries, nil
+}
+
+// NewReplayServer creates server connection struct that replays
+// session recorded in file with specified path, instead of
+// connecting to the server.
+// Sent requests are discarded, recorded responses are handled
+// after the Replay call.
+func NewReplayServer(path string) (*Server, error) {
+	entries, err := ReadSession(path)
+	if err != nil {
+		return nil, err
+	}
+	s := Server{
+		state:  Connected,
+		replay: &session{path, entries},
+		queue:  make(chan *queuedRequest, queueSize),
+		stop:   make(chan struct{}),
+	}
+	go s.writeRequests()
+	return &s, nil
+}
+
+// Record starts recording of the session in file with specified
+// path.
+// All requests written to the server and responses received from
+// the server are recorded with timestamps, until the connection
+// is closed.
+func (s *Server) Record(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create session file: %v", err)
+	}
+	s.recordMutex.Lock()
+	defer s.recordMutex.Unlock()
+	if s.recordFile != nil {
+		s.recordFile.Close()
+	}
+	s.recordFile = file
+	s.recorder = json.NewEncoder(file)
+	return nil
+}
+
+// Replay triggers response function for each response from the
+// replayed session, in order of recording.
+// With realtime enabled, delays between recorded responses are
+// preserved.
+// Blocks until all responses are handled or the connection is
+// closed.
+func (s *Server) Replay(realtime bool) error {
+	if s.replay == nil {
+		return fmt.Errorf("no session to replay")
+	}
+	var last time.Time
+	for _, e := range s.replay.entries {
+		if len(e.Response) < 1 {
+			continue
+		}
+		if realtime && !last.IsZero() {
+			select {
+			case <-time.After(e.Time.Sub(last)):
+			case <-s.stop:
+				return ErrClosed
+			}
+		}
+		last = e.Time
+		if s.Closed() {
+			return ErrClosed
+		}
+		resp, err := response.Unmarshal(e.Response)
+		if err != nil {
+			log.Err.Printf("Server replay: unable to unmarshal response: %v", err)
+			continue
+		}
+		s.dispatch(resp)
+	}
+	return nil
+}
+
+// record writes specified entry to the session file, with
+// current time as entry time.
+// Does nothing if the session is not recorded.
+// Recording has a separate mutex, so writing to the session
+// file doesn't block access to the connection state.
+func (s *Server) record(e SessionEntry) {
+	s.recordMutex.Lock()
+	defer s.recordMutex.Unlock()
+	if s.recorder == nil {
+		return
+	}
+	e.Time = time.Now()
+	err := s.recorder.Encode(e)
+	if err != nil {
+		log.Err.Printf("Server: unable to record session: %v", err)
+	}
+}
+
+// stopRecording stops recording of the session and closes
+// the session file.
+func (s *Server) stopRecording() {
+	s.recordMutex.Lock()
+	defer s.recordMutex.Unlock()
+	if s.recordFile == nil {
+		return
+	}
+	err := s.recordFile.Close()
+	if err != nil {
+		log.Err.Printf("Server: unable to close session file: %v", err)
+	}
+	s.recordFile = nil
+	s.recorder = nil
+}
@@ -0,0 +1,99 @@
+/*
+ * session_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package game
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isangeles/fire/request"
+	"github.com/isangeles/fire/response"
+)
+
+// TestSessionRecordReplay tests recording server session
+// and replaying it.
+func TestSessionRecordReplay(t *testing.T) {
+	// Record.
+	fire, server := newFireTest(t)
+	fire.Respond(response.Response{Error: []string{"error_test"}})
+	path := filepath.Join(t.TempDir(), "session_test")
+	err := server.Record(path)
+	if err != nil {
+		t.Fatalf("Unable to start recording: %v", err)
+	}
+	responses := make(chan response.Response, 1)
+	server.SetOnResponseFunc(func(r response.Response) {
+		responses <- r
+	})
+	err = server.Send(request.Request{Command: []string{"cmd_test"}})
+	if err != nil {
+		t.Fatalf("Unable to send request: %v", err)
+	}
+	select {
+	case <-responses:
+	case <-time.After(fireTestTimeout):
+		t.Fatalf("No response received")
+	}
+	server.Close()
+	// Test recording.
+	entries, err := ReadSession(path)
+	if err != nil {
+		t.Fatalf("Unable to read session: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Session entries invalid: %d != 2", len(entries))
+	}
+	if !strings.Contains(entries[0].Request, "cmd_test") {
+		t.Errorf("Recorded request invalid: %s", entries[0].Request)
+	}
+	if !strings.Contains(entries[1].Response, "error_test") {
+		t.Errorf("Recorded response invalid: %s", entries[1].Response)
+	}
+	if entries[1].Time.Before(entries[0].Time) {
+		t.Errorf("Recorded entries time invalid: %v < %v", entries[1].Time, entries[0].Time)
+	}
+	// Replay.
+	replay, err := NewReplayServer(path)
+	if err != nil {
+		t.Fatalf("Unable to create replay server: %v", err)
+	}
+	defer replay.Close()
+	replayed := make([]response.Response, 0)
+	replay.SetOnResponseFunc(func(r response.Response) {
+		replayed = append(replayed, r)
+	})
+	err = replay.Replay(false)
+	if err != nil {
+		t.Fatalf("Unable to replay session: %v", err)
+	}
+	// Test replay.
+	if len(replayed) != 1 {
+		t.Fatalf("Replayed responses invalid: %d != 1", len(replayed))
+	}
+	if len(replayed[0].Error) != 1 || replayed[0].Error[0] != "error_test" {
+		t.Errorf("Replayed response invalid: %v", replayed[0].Error)
+	}
+}
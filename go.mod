@@ -8,6 +8,7 @@ require (
 	github.com/isangeles/fire v0.0.0-20260414171202-137f65b8021c
 	github.com/isangeles/flame v0.0.0-20260407181657-41ac1c3c8249
 	github.com/isangeles/ignite v0.0.0-20260420205925-a42f1c26e2d0
+	golang.org/x/crypto v0.33.0
 )
 
 require github.com/isangeles/tmx v0.0.0-20230925150339-5410bc1b891b // indirect
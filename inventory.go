@@ -1,7 +1,7 @@
 /*
  * inventory.go
  *
- * Copyright 2021 Dariusz Sikora <dev@isangeles.pl>
+ * Copyright 2021-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -35,14 +35,14 @@ func inventoryDialog() error {
 		msg := lang.Text("no_game_err")
 		return fmt.Errorf(msg)
 	}
-	if activeGame.ActivePlayer() == nil {
+	if actingPlayer() == nil {
 		msg := lang.Text("no_pc_err")
 		return fmt.Errorf(msg)
 	}
 	// List items.
 	fmt.Printf("%s:\n", lang.Text("inventory_items"))
 	items := make(map[string]int)
-	for _, i := range activeGame.ActivePlayer().Inventory().Items() {
+	for _, i := range actingPlayer().Inventory().Items() {
 		items[lang.Text(i.ID())]++
 	}
 	for n, a := range items {
@@ -0,0 +1,569 @@
+/*
+ * editor.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+// Package with line editor for CLI input.
+// Editor supports cursor movement, input history with
+// reverse search and tab completion.
+// If input is not a terminal, editor reads plain lines
+// without editing.
+package lineedit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Function for tab completion.
+// Takes input text before the cursor and returns
+// candidates for the last word of this text.
+type CompleteFunc func(head string) []string
+
+// Struct for line editor.
+type Editor struct {
+	in       *os.File
+	out      io.Writer
+	history  *History
+	complete CompleteFunc
+}
+
+// Struct for state of the edited line.
+type lineState struct {
+	prompt    string
+	buf       []rune
+	pos       int
+	histIndex int
+	draft     []rune
+}
+
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyCtrlG     = 7
+	keyCtrlH     = 8
+	keyTab       = 9
+	keyLF        = 10
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyCR        = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlR     = 18
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEsc       = 27
+	keyBackspace = 127
+	// Keys for escape sequences.
+	keyUp = utf8.MaxRune + iota
+	keyDown
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyDelete
+	keyUnknown
+)
+
+const (
+	searchPrompt = "(reverse-i-search)"
+	// Default maximal number of history lines.
+	DefaultHistorySize = 1000
+)
+
+var ErrInterrupted = errors.New("interrupted")
+
+// New creates new line editor for specified input and output.
+// Editor history keeps up to DefaultHistorySize lines.
+func New(in *os.File, out io.Writer) *Editor {
+	e := Editor{
+		in:      in,
+		out:     out,
+		history: NewHistory(DefaultHistorySize),
+	}
+	return &e
+}
+
+// History returns editor input history.
+func (e *Editor) History() *History {
+	return e.history
+}
+
+// SetHistory sets specified history as editor input history.
+func (e *Editor) SetHistory(h *History) {
+	e.history = h
+}
+
+// SetCompleteFunc sets function for tab completion.
+func (e *Editor) SetCompleteFunc(f CompleteFunc) {
+	e.complete = f
+}
+
+// ReadLine prints specified prompt and reads line from the
+// editor input.
+// Returns io.EOF if there is no more input and ErrInterrupted
+// if the line was interrupted by the user(Ctrl-C).
+// Line is not added to the editor history.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	fd := int(e.in.Fd())
+	if !isTerminal(fd) {
+		return e.readPlain(prompt)
+	}
+	restore, err := makeRaw(fd)
+	if err != nil {
+		return e.readPlain(prompt)
+	}
+	defer restore()
+	return e.edit(prompt, e.in)
+}
+
+// ReadPassword prints specified prompt and reads line from
+// the editor input without echoing typed characters.
+// If input is not a terminal, plain line is read.
+func (e *Editor) ReadPassword(prompt string) (string, error) {
+	fd := int(e.in.Fd())
+	if !isTerminal(fd) {
+		return e.readPlain(prompt)
+	}
+	restore, err := makeRaw(fd)
+	if err != nil {
+		return "", fmt.Errorf("unable to disable input echo: %v", err)
+	}
+	defer restore()
+	return e.readSecret(prompt, e.in)
+}
+
+// readPlain prints specified prompt and reads line from the
+// editor input without editing.
+// Input is read byte by byte, so no data after the line is
+// consumed from the input.
+func (e *Editor) readPlain(prompt string) (string, error) {
+	fmt.Fprint(e.out, prompt)
+	line := make([]byte, 0)
+	b := make([]byte, 1)
+	for {
+		n, err := e.in.Read(b)
+		if n > 0 {
+			if b[0] == keyLF {
+				break
+			}
+			line = append(line, b[0])
+		}
+		if err == io.EOF && len(line) > 0 {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSuffix(string(line), "\r"), nil
+}
+
+// readSecret reads line with keys from specified reader
+// without echoing typed characters.
+// Only backspace and line kill(Ctrl-U) editing is supported.
+func (e *Editor) readSecret(prompt string, r io.Reader) (string, error) {
+	fmt.Fprint(e.out, prompt)
+	buf := make([]rune, 0)
+	for {
+		key, err := readKey(r)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case key == keyCR || key == keyLF:
+			fmt.Fprint(e.out, "\r\n")
+			return string(buf), nil
+		case key == keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", ErrInterrupted
+		case key == keyCtrlD && len(buf) < 1:
+			fmt.Fprint(e.out, "\r\n")
+			return "", io.EOF
+		case key == keyBackspace || key == keyCtrlH:
+			if len(buf) > 0 {
+				buf = buf[:len(buf)-1]
+			}
+		case key == keyCtrlU:
+			buf = buf[:0]
+		case key >= ' ' && key < utf8.MaxRune:
+			buf = append(buf, key)
+		}
+	}
+}
+
+// edit handles editing of the line with keys from
+// specified reader.
+func (e *Editor) edit(prompt string, r io.Reader) (string, error) {
+	ls := lineState{
+		prompt:    prompt,
+		histIndex: e.history.Len(),
+	}
+	e.refresh(&ls)
+	lastKey := rune(0)
+	for {
+		key, err := readKey(r)
+		if err != nil {
+			return "", err
+		}
+		switch key {
+		case keyCR, keyLF:
+			fmt.Fprint(e.out, "\r\n")
+			return string(ls.buf), nil
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", ErrInterrupted
+		case keyCtrlD:
+			if len(ls.buf) < 1 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			ls.delete()
+		case keyDelete:
+			ls.delete()
+		case keyBackspace, keyCtrlH:
+			ls.backspace()
+		case keyLeft, keyCtrlB:
+			if ls.pos > 0 {
+				ls.pos--
+			}
+		case keyRight, keyCtrlF:
+			if ls.pos < len(ls.buf) {
+				ls.pos++
+			}
+		case keyHome, keyCtrlA:
+			ls.pos = 0
+		case keyEnd, keyCtrlE:
+			ls.pos = len(ls.buf)
+		case keyCtrlK:
+			ls.buf = ls.buf[:ls.pos]
+		case keyCtrlU:
+			ls.buf = ls.buf[ls.pos:]
+			ls.pos = 0
+		case keyCtrlW:
+			ls.deleteWord()
+		case keyUp, keyCtrlP:
+			e.historyPrev(&ls)
+		case keyDown, keyCtrlN:
+			e.historyNext(&ls)
+		case keyCtrlL:
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case keyTab:
+			e.completeWord(&ls, lastKey == keyTab)
+		case keyCtrlR:
+			line, accept, err := e.reverseSearch(&ls, r)
+			if err != nil {
+				return "", err
+			}
+			if accept {
+				fmt.Fprint(e.out, "\r\n")
+				return line, nil
+			}
+		case keyEsc, keyUnknown, keyCtrlG:
+			break
+		default:
+			if key >= ' ' && key < keyUp {
+				ls.insert(key)
+			}
+		}
+		lastKey = key
+		e.refresh(&ls)
+	}
+}
+
+// refresh prints specified line state.
+func (e *Editor) refresh(ls *lineState) {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", ls.prompt, string(ls.buf))
+	if ls.pos < len(ls.buf) {
+		fmt.Fprintf(e.out, "\x1b[%dD", len(ls.buf)-ls.pos)
+	}
+}
+
+// historyPrev replaces line with previous history line.
+func (e *Editor) historyPrev(ls *lineState) {
+	if ls.histIndex < 1 {
+		return
+	}
+	if ls.histIndex >= e.history.Len() {
+		ls.draft = ls.buf
+	}
+	ls.histIndex--
+	line, _ := e.history.Line(ls.histIndex + 1)
+	ls.set(line)
+}
+
+// historyNext replaces line with next history line, or
+// with the line edited before history navigation.
+func (e *Editor) historyNext(ls *lineState) {
+	if ls.histIndex >= e.history.Len() {
+		return
+	}
+	ls.histIndex++
+	if ls.histIndex >= e.history.Len() {
+		ls.set(string(ls.draft))
+		return
+	}
+	line, _ := e.history.Line(ls.histIndex + 1)
+	ls.set(line)
+}
+
+// completeWord completes the word before the cursor with
+// candidates from the complete function.
+// Candidates are printed if there is more than one and the
+// list flag is true.
+func (e *Editor) completeWord(ls *lineState, list bool) {
+	if e.complete == nil {
+		return
+	}
+	head := string(ls.buf[:ls.pos])
+	candidates := e.complete(head)
+	if len(candidates) < 1 {
+		fmt.Fprint(e.out, "\a")
+		return
+	}
+	start := ls.pos
+	for start > 0 && ls.buf[start-1] != ' ' {
+		start--
+	}
+	word := string(ls.buf[start:ls.pos])
+	if len(candidates) == 1 {
+		ls.replace(start, candidates[0]+" ")
+		return
+	}
+	prefix := commonPrefix(candidates)
+	if utf8.RuneCountInString(prefix) > utf8.RuneCountInString(word) {
+		ls.replace(start, prefix)
+		return
+	}
+	if !list {
+		fmt.Fprint(e.out, "\a")
+		return
+	}
+	fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+}
+
+// reverseSearch handles reverse history search with keys
+// from specified reader.
+// Returns true if the found line was accepted by the user.
+func (e *Editor) reverseSearch(ls *lineState, r io.Reader) (string, bool, error) {
+	query := make([]rune, 0)
+	index := e.history.Len()
+	match := ""
+	for {
+		fmt.Fprintf(e.out, "\r%s`%s': %s\x1b[K", searchPrompt, string(query), match)
+		key, err := readKey(r)
+		if err != nil {
+			return "", false, err
+		}
+		switch key {
+		case keyCR, keyLF:
+			return match, true, nil
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", false, ErrInterrupted
+		case keyCtrlG:
+			return "", false, nil
+		case keyCtrlR:
+			if i, line := e.history.Search(string(query), index); i > -1 {
+				index, match = i, line
+			}
+		case keyBackspace, keyCtrlH:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+			}
+			index, match = e.history.Search(string(query), e.history.Len())
+		default:
+			if key >= ' ' && key < keyUp {
+				query = append(query, key)
+				index, match = e.history.Search(string(query), e.history.Len())
+				continue
+			}
+			// Any other key ends search and keeps match for editing.
+			if len(match) > 0 {
+				ls.set(match)
+			}
+			return "", false, nil
+		}
+		if index < 0 {
+			index = e.history.Len()
+		}
+	}
+}
+
+// insert inserts specified rune at the cursor position.
+func (ls *lineState) insert(r rune) {
+	ls.buf = append(ls.buf, 0)
+	copy(ls.buf[ls.pos+1:], ls.buf[ls.pos:])
+	ls.buf[ls.pos] = r
+	ls.pos++
+}
+
+// delete removes rune under the cursor.
+func (ls *lineState) delete() {
+	if ls.pos >= len(ls.buf) {
+		return
+	}
+	ls.buf = append(ls.buf[:ls.pos], ls.buf[ls.pos+1:]...)
+}
+
+// backspace removes rune before the cursor.
+func (ls *lineState) backspace() {
+	if ls.pos < 1 {
+		return
+	}
+	ls.pos--
+	ls.delete()
+}
+
+// deleteWord removes word before the cursor.
+func (ls *lineState) deleteWord() {
+	start := ls.pos
+	for start > 0 && ls.buf[start-1] == ' ' {
+		start--
+	}
+	for start > 0 && ls.buf[start-1] != ' ' {
+		start--
+	}
+	ls.buf = append(ls.buf[:start], ls.buf[ls.pos:]...)
+	ls.pos = start
+}
+
+// replace replaces text between specified position
+// and the cursor with specified text.
+func (ls *lineState) replace(start int, text string) {
+	tail := append([]rune(text), ls.buf[ls.pos:]...)
+	ls.buf = append(ls.buf[:start], tail...)
+	ls.pos = start + utf8.RuneCountInString(text)
+}
+
+// set sets specified text as line and moves cursor
+// to the end of the line.
+func (ls *lineState) set(text string) {
+	ls.buf = []rune(text)
+	ls.pos = len(ls.buf)
+}
+
+// readKey reads key from specified reader.
+// Escape sequences are translated to key codes.
+func readKey(r io.Reader) (rune, error) {
+	b, err := readByte(r)
+	if err != nil {
+		return 0, err
+	}
+	if b == keyEsc {
+		return readEscape(r)
+	}
+	if b < utf8.RuneSelf {
+		return rune(b), nil
+	}
+	// Multi-byte UTF-8 character.
+	buf := []byte{b}
+	for !utf8.FullRune(buf) && len(buf) < utf8.UTFMax {
+		b, err := readByte(r)
+		if err != nil {
+			return 0, err
+		}
+		buf = append(buf, b)
+	}
+	key, _ := utf8.DecodeRune(buf)
+	return key, nil
+}
+
+// readEscape reads escape sequence from specified reader
+// and returns key code for this sequence.
+func readEscape(r io.Reader) (rune, error) {
+	b, err := readByte(r)
+	if err != nil {
+		return 0, err
+	}
+	if b != '[' && b != 'O' {
+		return keyEsc, nil
+	}
+	seq := ""
+	for {
+		b, err := readByte(r)
+		if err != nil {
+			return 0, err
+		}
+		seq += string(b)
+		if b >= 0x40 && b <= 0x7e {
+			break
+		}
+	}
+	switch seq {
+	case "A":
+		return keyUp, nil
+	case "B":
+		return keyDown, nil
+	case "C":
+		return keyRight, nil
+	case "D":
+		return keyLeft, nil
+	case "H", "1~", "7~":
+		return keyHome, nil
+	case "F", "4~", "8~":
+		return keyEnd, nil
+	case "3~":
+		return keyDelete, nil
+	default:
+		return keyUnknown, nil
+	}
+}
+
+// readByte reads single byte from specified reader.
+func readByte(r io.Reader) (byte, error) {
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n > 0 {
+			return b[0], nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// commonPrefix returns longest common prefix of
+// specified texts.
+func commonPrefix(texts []string) string {
+	if len(texts) < 1 {
+		return ""
+	}
+	prefix := []rune(texts[0])
+	for _, t := range texts[1:] {
+		rt := []rune(t)
+		i := 0
+		for i < len(prefix) && i < len(rt) && prefix[i] == rt[i] {
+			i++
+		}
+		prefix = prefix[:i]
+	}
+	return string(prefix)
+}
@@ -0,0 +1,121 @@
+/*
+ * editor_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package lineedit
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestEditCursor tests editing line with cursor movement.
+func TestEditCursor(t *testing.T) {
+	e := New(nil, new(bytes.Buffer))
+	// 'mve', 2x left, insert 'o', end, ' 10', Ctrl-W, '20', enter.
+	in := strings.NewReader("mve\x1b[D\x1b[Do\x05 10\x1720\r")
+	line, err := e.edit(">", in)
+	if err != nil {
+		t.Fatalf("Unable to edit line: %v", err)
+	}
+	if line != "move 20" {
+		t.Errorf("Edited line invalid: '%s' != 'move 20'", line)
+	}
+}
+
+// TestEditHistory tests history navigation and
+// reverse search.
+func TestEditHistory(t *testing.T) {
+	e := New(nil, new(bytes.Buffer))
+	e.History().Add("$move 10 10")
+	e.History().Add("$target goblin")
+	e.History().Add("$inventory")
+	// Two lines up.
+	line, err := e.edit(">", strings.NewReader("\x1b[A\x1b[A\r"))
+	if err != nil {
+		t.Fatalf("Unable to edit line: %v", err)
+	}
+	if line != "$target goblin" {
+		t.Errorf("History line invalid: '%s' != '$target goblin'", line)
+	}
+	// Reverse search.
+	line, err = e.edit(">", strings.NewReader("\x12mov\r"))
+	if err != nil {
+		t.Fatalf("Unable to edit line: %v", err)
+	}
+	if line != "$move 10 10" {
+		t.Errorf("Search line invalid: '%s' != '$move 10 10'", line)
+	}
+	// EOF.
+	_, err = e.edit(">", strings.NewReader("\x04"))
+	if err != io.EOF {
+		t.Errorf("EOF error invalid: %v", err)
+	}
+}
+
+// TestEditComplete tests tab completion.
+func TestEditComplete(t *testing.T) {
+	e := New(nil, new(bytes.Buffer))
+	e.SetCompleteFunc(func(head string) []string {
+		words := []string{"move", "movetar", "inventory"}
+		fields := strings.Fields(head)
+		cands := make([]string, 0)
+		for _, w := range words {
+			if strings.HasPrefix(w, fields[len(fields)-1]) {
+				cands = append(cands, w)
+			}
+		}
+		return cands
+	})
+	line, err := e.edit(">", strings.NewReader("inv\t\r"))
+	if err != nil {
+		t.Fatalf("Unable to edit line: %v", err)
+	}
+	if line != "inventory " {
+		t.Errorf("Completed line invalid: '%s' != 'inventory '", line)
+	}
+	line, err = e.edit(">", strings.NewReader("mo\t\r"))
+	if err != nil {
+		t.Fatalf("Unable to edit line: %v", err)
+	}
+	if line != "move" {
+		t.Errorf("Completed line invalid: '%s' != 'move'", line)
+	}
+}
+
+// TestReadSecret tests reading line without echo.
+func TestReadSecret(t *testing.T) {
+	out := new(bytes.Buffer)
+	e := New(nil, out)
+	line, err := e.readSecret("pass:", strings.NewReader("pa\x7fss\r"))
+	if err != nil {
+		t.Fatalf("Unable to read secret: %v", err)
+	}
+	if line != "pss" {
+		t.Errorf("Secret line invalid: '%s' != 'pss'", line)
+	}
+	if out.String() != "pass:\r\n" {
+		t.Errorf("Secret echoed: '%q'", out.String())
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * history.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package lineedit
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Struct for input history.
+type History struct {
+	mutex sync.RWMutex
+	lines []string
+	max   int
+}
+
+// NewHistory creates new input history that keeps up
+// to specified number of lines.
+// Non-positive max value means no limit.
+func NewHistory(max int) *History {
+	h := History{max: max}
+	return &h
+}
+
+// Add adds specified line to the history.
+// Empty lines and lines equal to the last history
+// line are skipped.
+func (h *History) Add(line string) {
+	if len(strings.TrimSpace(line)) < 1 {
+		return
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if len(h.lines) > 0 && h.lines[len(h.lines)-1] == line {
+		return
+	}
+	h.lines = append(h.lines, line)
+	if h.max > 0 && len(h.lines) > h.max {
+		h.lines = h.lines[len(h.lines)-h.max:]
+	}
+}
+
+// Lines returns all history lines, from the oldest
+// to the newest one.
+func (h *History) Lines() []string {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	lines := make([]string, len(h.lines))
+	copy(lines, h.lines)
+	return lines
+}
+
+// Len returns number of history lines.
+func (h *History) Len() int {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return len(h.lines)
+}
+
+// Line returns history line with specified number.
+// Lines are numbered from 1, negative numbers count
+// back from the newest line(-1 is the newest line).
+func (h *History) Line(n int) (string, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	if n < 0 {
+		n = len(h.lines) + n + 1
+	}
+	if n < 1 || n > len(h.lines) {
+		return "", false
+	}
+	return h.lines[n-1], true
+}
+
+// Find returns the newest history line that starts with
+// specified prefix.
+func (h *History) Find(prefix string) (string, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	for i := len(h.lines) - 1; i >= 0; i-- {
+		if strings.HasPrefix(h.lines[i], prefix) {
+			return h.lines[i], true
+		}
+	}
+	return "", false
+}
+
+// Search searches history backward, starting from the line
+// before line with specified index, for line that contains
+// specified text.
+// Returns index of found line or -1 if there is no such line.
+func (h *History) Search(text string, from int) (int, string) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	if from > len(h.lines) {
+		from = len(h.lines)
+	}
+	for i := from - 1; i >= 0; i-- {
+		if strings.Contains(h.lines[i], text) {
+			return i, h.lines[i]
+		}
+	}
+	return -1, ""
+}
+
+// Load loads history lines from file with specified path.
+func (h *History) Load(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("unable to open history file: %v", err)
+	}
+	defer file.Close()
+	scan := bufio.NewScanner(file)
+	for scan.Scan() {
+		h.Add(scan.Text())
+	}
+	if err := scan.Err(); err != nil {
+		return fmt.Errorf("unable to read history file: %v", err)
+	}
+	return nil
+}
+
+// Save saves history lines in file with specified path.
+func (h *History) Save(path string) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return fmt.Errorf("unable to create history directory: %v", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to create history file: %v", err)
+	}
+	defer file.Close()
+	w := bufio.NewWriter(file)
+	for _, l := range h.Lines() {
+		w.WriteString(l + "\n")
+	}
+	err = w.Flush()
+	if err != nil {
+		return fmt.Errorf("unable to write history file: %v", err)
+	}
+	return nil
+}
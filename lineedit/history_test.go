@@ -0,0 +1,96 @@
+/*
+ * history_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package lineedit
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestHistorySaveLoad tests saving and loading history file.
+func TestHistorySaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	h := NewHistory(2)
+	h.Add("$move 10 10")
+	h.Add("$target goblin")
+	h.Add("$target goblin")
+	h.Add("$inventory")
+	err := h.Save(path)
+	if err != nil {
+		t.Fatalf("Unable to save history: %v", err)
+	}
+	loaded := NewHistory(0)
+	err = loaded.Load(path)
+	if err != nil {
+		t.Fatalf("Unable to load history: %v", err)
+	}
+	lines := loaded.Lines()
+	if len(lines) != 2 || lines[0] != "$target goblin" || lines[1] != "$inventory" {
+		t.Errorf("Loaded history invalid: %v", lines)
+	}
+}
+
+// TestHistoryLoadLimit tests limiting history lines
+// loaded from history file.
+func TestHistoryLoadLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	h := NewHistory(0)
+	h.Add("$move 10 10")
+	h.Add("$target goblin")
+	h.Add("$inventory")
+	err := h.Save(path)
+	if err != nil {
+		t.Fatalf("Unable to save history: %v", err)
+	}
+	loaded := NewHistory(1)
+	err = loaded.Load(path)
+	if err != nil {
+		t.Fatalf("Unable to load history: %v", err)
+	}
+	lines := loaded.Lines()
+	if len(lines) != 1 || lines[0] != "$inventory" {
+		t.Errorf("Loaded history invalid: %v", lines)
+	}
+}
+
+// TestHistoryRecall tests retrieving history lines by
+// number and prefix.
+func TestHistoryRecall(t *testing.T) {
+	h := NewHistory(0)
+	h.Add("$move 10 10")
+	h.Add("$target goblin")
+	h.Add("$move 20 20")
+	if l, _ := h.Line(1); l != "$move 10 10" {
+		t.Errorf("History line 1 invalid: '%s'", l)
+	}
+	if l, _ := h.Line(-1); l != "$move 20 20" {
+		t.Errorf("History line -1 invalid: '%s'", l)
+	}
+	if _, ok := h.Line(4); ok {
+		t.Errorf("History line 4 found")
+	}
+	if l, _ := h.Find("$mo"); l != "$move 20 20" {
+		t.Errorf("History line with prefix invalid: '%s'", l)
+	}
+}
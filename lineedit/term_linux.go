@@ -0,0 +1,72 @@
+//go:build linux
+
+/*
+ * term_linux.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package lineedit
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// isTerminal checks if specified file descriptor
+// refers to a terminal.
+func isTerminal(fd int) bool {
+	var t syscall.Termios
+	return ioctl(fd, syscall.TCGETS, &t) == nil
+}
+
+// makeRaw puts terminal with specified file descriptor in the raw
+// mode and returns function that restores the previous terminal state.
+func makeRaw(fd int) (func(), error) {
+	var old syscall.Termios
+	err := ioctl(fd, syscall.TCGETS, &old)
+	if err != nil {
+		return nil, err
+	}
+	raw := old
+	raw.Iflag &^= syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.IEXTEN | syscall.ISIG
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	err = ioctl(fd, syscall.TCSETS, &raw)
+	if err != nil {
+		return nil, err
+	}
+	restore := func() {
+		ioctl(fd, syscall.TCSETS, &old)
+	}
+	return restore, nil
+}
+
+// ioctl calls ioctl system call with specified terminal
+// request and terminal state.
+func ioctl(fd int, req uintptr, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), req,
+		uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+//go:build !linux
+
+/*
+ * term_other.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package lineedit
+
+import (
+	"fmt"
+)
+
+// isTerminal checks if specified file descriptor
+// refers to a terminal.
+// Always returns false, line editing is supported only
+// on Linux terminals.
+func isTerminal(fd int) bool {
+	return false
+}
+
+// makeRaw puts terminal with specified file descriptor in the raw
+// mode and returns function that restores the previous terminal state.
+func makeRaw(fd int) (func(), error) {
+	return nil, fmt.Errorf("raw terminal mode not supported")
+}
@@ -1,7 +1,7 @@
 /*
  * loadgame.go
  *
- * Copyright 2019-2023 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -35,18 +35,23 @@ import (
 
 	"github.com/isangeles/flame"
 	flamedata "github.com/isangeles/flame/data"
+	flameres "github.com/isangeles/flame/data/res"
 	"github.com/isangeles/flame/data/res/lang"
+	"github.com/isangeles/flame/effect"
+	"github.com/isangeles/flame/objects"
+	"github.com/isangeles/flame/serial"
 
 	"github.com/isangeles/fire/request"
 
-	"github.com/isangeles/burnsh/config"
 	"github.com/isangeles/burnsh/game"
 	"github.com/isangeles/burnsh/log"
 )
 
 // loadGameDialog starts CLI dialog for loading
 // saved game.
-func loadGameDialog() error {
+// Save name can be specified as an argument, in that
+// case the dialog skips the save prompt.
+func loadGameDialog(args ...string) error {
 	if mod == nil {
 		return fmt.Errorf("no module loaded")
 	}
@@ -57,8 +62,11 @@ func loadGameDialog() error {
 		return fmt.Errorf("unable to retrieve save files: %v", err)
 	}
 	savename := ""
+	if len(args) > 0 {
+		savename = strings.TrimSuffix(args[0], SaveExt) + SaveExt
+	}
 	scan := bufio.NewScanner(os.Stdin)
-	for accept := false; !accept; {
+	for accept := len(savename) > 0; !accept; {
 		fmt.Printf("%s:\n", lang.Text("loadgame_saves"))
 		for i, s := range saves {
 			fmt.Printf("[%d]%v\n", i, s)
@@ -77,55 +85,213 @@ func loadGameDialog() error {
 		}
 		accept = true
 	}
+	savename = strings.TrimSuffix(savename, SaveExt)
 	// Handle game server.
 	if server != nil {
-		savename = strings.ReplaceAll(savename, SaveExt, "")
 		req := request.Request{Load: savename}
 		err := server.Send(req)
 		if err != nil {
 			return fmt.Errorf("unable to send load request: %v", err)
 		}
+		// Server saves made by other clients have no
+		// local CLI state.
+		cliPath := filepath.Join(path, savename+SaveExt)
+		if _, err := os.Stat(cliPath); err != nil {
+			log.Dbg.Printf("No local CLI state for server save: %s", savename)
+			return nil
+		}
+		cliSave, err := loadCLI(cliPath)
+		if err != nil {
+			log.Err.Printf("Unable to load CLI state: %v", err)
+			return nil
+		}
+		restoreShell(cliSave)
 		return nil
 	}
 	// CLI.
-	savename = strings.TrimSuffix(savename, flamedata.ModuleFileExt)
-	cliSavePath := filepath.Join(mod.Conf().Path, ModuleSavesPath, savename+SaveExt)
-	cliSave, err := loadCLI(cliSavePath)
+	g, cliSave, err := loadGame(path, savename)
+	if err != nil {
+		return err
+	}
+	restoreShell(cliSave)
+	startGame(g)
+	return nil
+}
+
+// loadGame loads game from save with specified name in
+// directory with specified path.
+// Returns new game with module and players restored from
+// the save, and the CLI save itself.
+// Serials are reset only after the save was checked, so
+// a failed load leaves the current game untouched.
+func loadGame(dir, name string) (*game.Game, *CLISave, error) {
+	cliSave, err := loadCLI(filepath.Join(dir, name+SaveExt))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to load CLI state: %v", err)
+	}
+	modData, err := flamedata.ImportModule(filepath.Join(dir, name+flamedata.ModuleFileExt))
 	if err != nil {
-		return fmt.Errorf("unable to load CLI state: %v", err)
+		return nil, nil, fmt.Errorf("unable to import module file: %v", err)
+	}
+	for _, pcSave := range cliSave.Players {
+		if !moduleCharacter(modData, pcSave.ID, pcSave.Serial) {
+			return nil, nil, fmt.Errorf("unable to find player character: %s",
+				idSerial(pcSave.ID, pcSave.Serial))
+		}
 	}
+	serial.Reset()
+	g := game.New(flame.NewModule(modData))
+	var activePlayer *game.Player
 	for _, pcSave := range cliSave.Players {
-		c := activeGame.Chapter().Character(pcSave.ID, pcSave.Serial)
+		c := g.Chapter().Character(pcSave.ID, pcSave.Serial)
 		if c == nil {
-			log.Err.Printf("load game: unable to find pc: %s%s", pcSave.ID, pcSave.Serial)
+			return nil, nil, fmt.Errorf("unable to find player character: %s",
+				idSerial(pcSave.ID, pcSave.Serial))
+		}
+		pc := game.NewPlayer(c, g)
+		restorePlayer(g, pc, pcSave)
+		g.AddPlayer(pc)
+		if pcSave.Active || activePlayer == nil {
+			activePlayer = pc
 		}
-		activeGame.AddPlayer(game.NewPlayer(c, activeGame))
 	}
-	if len(activeGame.Players()) > 0 {
-		activeGame.SetActivePlayer(activeGame.Players()[0])
+	g.SetActivePlayer(activePlayer)
+	return g, cliSave, nil
+}
+
+// moduleCharacter checks if character with specified ID and serial
+// is present in specified module data.
+func moduleCharacter(data flameres.ModuleData, id, serial string) bool {
+	for _, chars := range [][]flameres.CharacterData{
+		data.Resources.Characters,
+		data.Chapter.Resources.Characters,
+	} {
+		for _, cd := range chars {
+			if cd.ID == id && cd.Serial == serial {
+				return true
+			}
+		}
 	}
-	// Game.
-	savepath := filepath.Join(config.ModulesPath, savename)
-	modData, err := flamedata.ImportModule(savepath)
-	if err != nil {
-		return fmt.Errorf("unable to import module file: %v", err)
+	return false
+}
+
+// restorePlayer restores targets and log of specified player
+// of specified game from specified player save.
+func restorePlayer(g *game.Game, pc *game.Player, save PlayerSave) {
+	for _, m := range save.Log {
+		pc.Log().Add(objects.Message{
+			Time:       m.Time,
+			Translated: m.Translated,
+			Text:       m.Text,
+		})
+	}
+	area := g.Chapter().ObjectArea(pc.Character)
+	if area == nil {
+		return
+	}
+	for _, tarSave := range save.Targets {
+		for _, ob := range area.Objects() {
+			tar, ok := ob.(effect.Target)
+			if !ok || tar.ID() != tarSave.ID || tar.Serial() != tarSave.Serial {
+				continue
+			}
+			pc.Character.SetTarget(tar)
+		}
+	}
+}
+
+// restoreShell restores shell state from specified CLI save.
+// Command history is appended to the current history,
+// saved playable characters and name translations are added
+// to the ones already known by the shell, known name
+// translations are replaced with the saved ones.
+func restoreShell(save *CLISave) {
+	if editor != nil {
+		for _, l := range save.History {
+			if keepInHistory(l) {
+				editor.History().Add(l)
+			}
+		}
+	}
+	for _, cd := range save.PlayableChars {
+		known := false
+		for _, pcd := range playableChars {
+			if pcd.ID == cd.ID {
+				known = true
+				break
+			}
+		}
+		if !known {
+			playableChars = append(playableChars, cd)
+		}
+	}
+	for _, t := range save.Translations {
+		lang.AddTranslation(t)
+		known := false
+		for i, n := range charNames {
+			if n.ID == t.ID {
+				charNames[i] = t
+				known = true
+				break
+			}
+		}
+		if !known {
+			charNames = append(charNames, t)
+		}
 	}
-	m := flame.NewModule(modData)
-	activeGame = game.New(m)
-	return nil
 }
 
 // loadCLI loads CLI save file from specified path.
+// Saves in older format versions are migrated to
+// the current version.
 func loadCLI(path string) (*CLISave, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("unable to open save file: %v", err)
 	}
-	data, _ := ioutil.ReadAll(file)
+	defer file.Close()
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read save file: %v", err)
+	}
 	cliSave := new(CLISave)
 	err = xml.Unmarshal(data, cliSave)
 	if err != nil {
 		return nil, fmt.Errorf("unable to unmarshal xml data: %v", err)
 	}
+	err = migrateCLI(cliSave)
+	if err != nil {
+		return nil, fmt.Errorf("unable to migrate save: %v", err)
+	}
 	return cliSave, nil
 }
+
+// Migrations of CLI save format, migration under index N
+// updates save from version N to version N+1.
+var saveMigrations = []func(save *CLISave){
+	// Version 0 saves have no active player flag, the first
+	// player was always the active player.
+	func(save *CLISave) {
+		for _, pcSave := range save.Players {
+			if pcSave.Active {
+				return
+			}
+		}
+		if len(save.Players) > 0 {
+			save.Players[0].Active = true
+		}
+	},
+}
+
+// migrateCLI migrates specified CLI save to the current
+// save format version.
+func migrateCLI(save *CLISave) error {
+	if save.Version > SaveVersion {
+		return fmt.Errorf("unsupported save version: %d", save.Version)
+	}
+	for save.Version < SaveVersion {
+		saveMigrations[save.Version](save)
+		save.Version++
+	}
+	return nil
+}
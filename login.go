@@ -1,7 +1,7 @@
 /*
  * login.go
  *
- * Copyright 2020 Dariusz Sikora <dev@isangeles.pl>
+ * Copyright 2020-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -26,6 +26,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"net"
 	"os"
 
 	"github.com/isangeles/flame/data/res/lang"
@@ -33,31 +34,46 @@ import (
 	"github.com/isangeles/fire/request"
 
 	"github.com/isangeles/burnsh/config"
+	"github.com/isangeles/burnsh/credentials"
+	"github.com/isangeles/burnsh/lineedit"
+	"github.com/isangeles/burnsh/log"
 )
 
+const loginForgetArg = "-f"
+
 var logged bool
 
 // login start CLI dialog for game server login.
-func loginDialog() error {
+// Login and password can be specified as arguments, in that
+// case the dialog skips the credentials prompt. For login
+// without password, only the password is asked, like in
+// loginAs.
+// Credentials remembered in the credentials file are used
+// if not specified in arguments or config, '-f' argument
+// removes remembered credentials for the current server.
+func loginDialog(args ...string) error {
 	if server == nil {
 		return fmt.Errorf("No server connection")
 	}
+	if len(args) > 0 && args[0] == loginForgetArg {
+		return forgetCredentials()
+	}
+	if len(args) == 1 {
+		return loginAs(args[0])
+	}
 	loginReq := request.Login{config.ServerLogin, config.ServerPass}
+	if len(args) > 1 {
+		loginReq = request.Login{args[0], args[1]}
+	}
 	if len(loginReq.ID) < 1 || len(loginReq.Pass) < 1 {
-		scan := bufio.NewScanner(os.Stdin)
-		fmt.Printf("%s:", lang.Text("cli_login_id"))
-		for scan.Scan() {
-			loginReq.ID = scan.Text()
-			if len(loginReq.ID) > 0 {
-				break
-			}
-		}
-		fmt.Printf("%s:", lang.Text("cli_login_pass"))
-		for scan.Scan() {
-			loginReq.Pass = scan.Text()
-			if len(loginReq.Pass) > 0 {
-				break
+		c, ok := rememberedCredentials()
+		loginReq = request.Login{c.Login, c.Pass}
+		if !ok {
+			login, err := credentialsDialog()
+			if err != nil {
+				return err
 			}
+			loginReq = login
 		}
 	}
 	req := request.Request{Login: []request.Login{loginReq}}
@@ -68,3 +84,153 @@ func loginDialog() error {
 	}
 	return nil
 }
+
+// loginAs logs in to the current game server as user with
+// specified login.
+// Password remembered in the credentials file is used, or
+// asked from the user if there is no password for the login.
+func loginAs(login string) error {
+	c, ok := rememberedCredentials()
+	if !ok || c.Login != login {
+		pass, err := readPassword(fmt.Sprintf("%s(%s):", lang.Text("cli_login_pass"), login))
+		if err != nil {
+			return fmt.Errorf("unable to read password: %v", err)
+		}
+		c = credentials.Credentials{login, pass}
+	}
+	req := request.Request{Login: []request.Login{{c.Login, c.Pass}}}
+	err := server.Send(req)
+	if err != nil {
+		return fmt.Errorf("Unable to send login request: %v",
+			err)
+	}
+	return nil
+}
+
+// credentialsDialog starts CLI dialog for game server
+// credentials, and offers to remember them in the
+// credentials file.
+func credentialsDialog() (loginReq request.Login, err error) {
+	scan := bufio.NewScanner(os.Stdin)
+	fmt.Printf("%s:", lang.Text("cli_login_id"))
+	for scan.Scan() {
+		loginReq.ID = scan.Text()
+		if len(loginReq.ID) > 0 {
+			break
+		}
+	}
+	for len(loginReq.Pass) < 1 {
+		loginReq.Pass, err = readPassword(lang.Text("cli_login_pass") + ":")
+		if err != nil {
+			return loginReq, fmt.Errorf("unable to read password: %v", err)
+		}
+	}
+	fmt.Printf("%s:", lang.Text("cli_login_remember"))
+	if !scan.Scan() || scan.Text() != "y" {
+		return loginReq, nil
+	}
+	err = rememberCredentials(credentials.Credentials{loginReq.ID, loginReq.Pass})
+	if err != nil {
+		log.Err.Printf("Unable to remember credentials: %v", err)
+	}
+	return loginReq, nil
+}
+
+// rememberedCredentials returns credentials for the current
+// server from the credentials file.
+// Credentials are remembered for the server host and port
+// specified on connect, not the resolved or proxy address.
+// Asks user for the passphrase to the credentials file.
+func rememberedCredentials() (credentials.Credentials, bool) {
+	if _, err := os.Stat(config.CredentialsPath()); err != nil {
+		return credentials.Credentials{}, false
+	}
+	store, _, err := unlockCredentials()
+	if err != nil {
+		log.Err.Printf("Unable to load credentials: %v", err)
+		return credentials.Credentials{}, false
+	}
+	return store.Get(serverAddr)
+}
+
+// rememberCredentials saves specified credentials for the
+// current server in the credentials file.
+func rememberCredentials(c credentials.Credentials) error {
+	return storeCredentials(serverAddr, c)
+}
+
+// migrateCredentials moves server login and password from the
+// deprecated config value to the credentials file, for the server
+// from the config, and removes the value from the config file.
+// Does nothing if there are no credentials in the config.
+func migrateCredentials() error {
+	if len(config.ServerLogin) < 1 || len(config.ServerHost) < 1 {
+		return nil
+	}
+	fmt.Printf("%s\n", lang.Text("cli_login_migrate"))
+	addr := net.JoinHostPort(config.ServerHost, config.ServerPort)
+	err := storeCredentials(addr, credentials.Credentials{config.ServerLogin, config.ServerPass})
+	if err != nil {
+		return err
+	}
+	config.DropServerUser()
+	return config.Save()
+}
+
+// storeCredentials saves specified credentials for the server
+// with specified address in the credentials file.
+func storeCredentials(addr string, c credentials.Credentials) error {
+	store := credentials.NewStore()
+	passphrase := ""
+	if _, err := os.Stat(config.CredentialsPath()); err == nil {
+		s, p, err := unlockCredentials()
+		if err != nil {
+			return err
+		}
+		store, passphrase = s, p
+	}
+	for len(passphrase) < 1 {
+		p, err := readPassword(lang.Text("cli_login_new_passphrase") + ":")
+		if err != nil {
+			return fmt.Errorf("unable to read passphrase: %v", err)
+		}
+		passphrase = p
+	}
+	store.Set(addr, c)
+	return store.Save(config.CredentialsPath(), passphrase)
+}
+
+// forgetCredentials removes credentials for the current
+// server from the credentials file.
+func forgetCredentials() error {
+	store, passphrase, err := unlockCredentials()
+	if err != nil {
+		return fmt.Errorf("unable to load credentials: %v", err)
+	}
+	store.Remove(serverAddr)
+	return store.Save(config.CredentialsPath(), passphrase)
+}
+
+// unlockCredentials asks user for the passphrase and loads
+// credentials store from the credentials file.
+// Returns credentials store and passphrase.
+func unlockCredentials() (*credentials.Store, string, error) {
+	passphrase, err := readPassword(lang.Text("cli_login_passphrase") + ":")
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to read passphrase: %v", err)
+	}
+	store, err := credentials.Load(config.CredentialsPath(), passphrase)
+	if err != nil {
+		return nil, "", err
+	}
+	return store, passphrase, nil
+}
+
+// readPassword prints specified prompt and reads password
+// from the standard input, without echo.
+func readPassword(prompt string) (string, error) {
+	if editor != nil {
+		return editor.ReadPassword(prompt)
+	}
+	return lineedit.New(os.Stdin, os.Stdout).ReadPassword(prompt)
+}
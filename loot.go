@@ -1,7 +1,7 @@
 /*
  * loot.go
  *
- * Copyright 2019-2025 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -36,18 +36,18 @@ func lootDialog() error {
 	if activeGame == nil {
 		return fmt.Errorf("no game started")
 	}
-	if activeGame.ActivePlayer() == nil {
+	if actingPlayer() == nil {
 		return fmt.Errorf("no active player")
 	}
-	if len(activeGame.ActivePlayer().Targets()) < 1 {
+	if len(actingPlayer().Targets()) < 1 {
 		return fmt.Errorf("no target")
 	}
-	tar := activeGame.ActivePlayer().Targets()[0]
+	tar := actingPlayer().Targets()[0]
 	ob, ok := tar.(*character.Character)
 	if ok && ob.Live() && !ob.OpenLoot() {
 		return fmt.Errorf("target is not lootable")
 	}
-	err := activeGame.TransferItems(activeGame.ActivePlayer(), ob, lootItems(ob.Inventory().Items())...)
+	err := activeGame.TransferItems(actingPlayer(), ob, lootItems(ob.Inventory().Items())...)
 	if err != nil {
 		return fmt.Errorf("unable to transfer items: %v", err)
 	}
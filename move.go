@@ -1,7 +1,7 @@
 /*
  * move.go
  *
- * Copyright 2019-2020 Dariusz Sikora <dev@isangeles.pl>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -27,20 +27,46 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
-	
+	"strings"
+
 	"github.com/isangeles/flame/data/res/lang"
+	"github.com/isangeles/flame/effect"
+
+	"github.com/isangeles/burnsh/config"
+	"github.com/isangeles/burnsh/game"
 )
 
-// moveDialog starts dialog for setting a destination point
-// for the active player.
-func moveDialog() error {
+var ModulePointsPath = "burnsh/points"
+
+// Struct for named point defined in the module.
+type modulePoint struct {
+	Name string
+	Area string
+	X, Y float64
+}
+
+// moveDialog starts dialog for moving the active player.
+// Destination can be specified as arguments, in that case the
+// dialog skips the position prompt. Arguments are waypoints in
+// the form of XY position, or name of the module point or ID
+// of the area object, the player moves along the route through
+// all waypoints.
+func moveDialog(args ...string) error {
 	if activeGame == nil {
 		return fmt.Errorf("%s\n", lang.Text("no_game_err"))
 	}
-	if activeGame.ActivePlayer() == nil {
+	if actingPlayer() == nil {
 		return fmt.Errorf("%s\n", lang.Text("no_pc_err"))
 	}
+	if len(args) > 0 {
+		waypoints, err := moveWaypoints(actingPlayer(), args...)
+		if err != nil {
+			return err
+		}
+		return moveActivePlayer(waypoints...)
+	}
 	scan := bufio.NewScanner(os.Stdin)
 	for {
 		fmt.Printf("%s:", lang.Text("move_enter_x_position"))
@@ -61,8 +87,123 @@ func moveDialog() error {
 				input)
 			continue
 		}
-		activeGame.ActivePlayer().SetDestPoint(x, y)
-		break
+		return moveActivePlayer(game.Point{X: x, Y: y})
+	}
+}
+
+// moveActivePlayer moves the active player along the route
+// through specified waypoints.
+func moveActivePlayer(waypoints ...game.Point) error {
+	return actingPlayer().MoveTo(waypoints...)
+}
+
+// moveWaypoints returns waypoints specified by move command
+// arguments, for specified player.
+// Waypoint is specified as XY position or a name of the
+// destination.
+func moveWaypoints(pc *game.Player, args ...string) ([]game.Point, error) {
+	waypoints := make([]game.Point, 0)
+	for i := 0; i < len(args); i++ {
+		x, err := strconv.ParseFloat(args[i], 64)
+		if err != nil {
+			p, err := namedDestination(pc, args[i])
+			if err != nil {
+				return nil, err
+			}
+			waypoints = append(waypoints, p)
+			continue
+		}
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("%s: %v", lang.Text("invalid_input_err"), args)
+		}
+		i++
+		y, err := strconv.ParseFloat(args[i], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", lang.Text("cli_nan_error"), args[i])
+		}
+		waypoints = append(waypoints, game.Point{X: x, Y: y})
+	}
+	return waypoints, nil
+}
+
+// namedDestination returns position of module point or area
+// object with specified name, in the area of specified player.
+// Area objects are specified by ID, optionally followed by
+// serial value.
+func namedDestination(pc *game.Player, name string) (game.Point, error) {
+	a := activeGame.Chapter().ObjectArea(pc)
+	if a == nil {
+		return game.Point{}, fmt.Errorf("%s", lang.Text("no_pc_area_err"))
+	}
+	if mod != nil {
+		points, err := modulePoints(filepath.Join(mod.Conf().Path, ModulePointsPath))
+		if err != nil {
+			return game.Point{}, err
+		}
+		for _, p := range points {
+			if p.Name == name && p.Area == a.ID() {
+				return game.Point{X: p.X, Y: p.Y}, nil
+			}
+		}
+	}
+	for _, ob := range a.Objects() {
+		tar, ok := ob.(effect.Target)
+		if !ok || !matchIDSerial(name, tar.ID(), tar.Serial()) {
+			continue
+		}
+		x, y := tar.Position()
+		return game.Point{X: x, Y: y}, nil
+	}
+	return game.Point{}, fmt.Errorf("%s: %s", lang.Text("move_dest_not_found_err"), name)
+}
+
+// modulePoints reads named points from the module points file
+// with specified path.
+// Each line of the file contains point name, area ID and XY
+// position, separated by ';'.
+// Returns no points if the points file doesn't exist.
+func modulePoints(path string) ([]modulePoint, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read module points file: %v", err)
+	}
+	points := make([]modulePoint, 0)
+	for i, l := range strings.Split(string(data), "\n") {
+		l = strings.TrimSpace(l)
+		if len(l) < 1 || strings.HasPrefix(l, config.CommentPrefix) {
+			continue
+		}
+		values := strings.Split(l, ";")
+		if len(values) < 4 {
+			return nil, fmt.Errorf("invalid module point at line %d: %s", i+1, l)
+		}
+		p := modulePoint{Name: values[0], Area: values[1]}
+		p.X, err = strconv.ParseFloat(values[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid module point at line %d: %v", i+1, err)
+		}
+		p.Y, err = strconv.ParseFloat(values[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid module point at line %d: %v", i+1, err)
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// handleArrival notifies the user that specified player ended
+// the route, on the destination or stuck on the way if
+// specified error is not nil.
+func handleArrival(pc *game.Player, err error) {
+	x, y := pc.Position()
+	if err != nil {
+		fmt.Printf("%s: %s: %fx%f\n", lang.Text("move_stuck"),
+			lang.Text(pc.ID()), x, y)
+		return
 	}
-	return nil
+	fmt.Printf("%s: %s: %fx%f\n", lang.Text("move_arrived"),
+		lang.Text(pc.ID()), x, y)
 }
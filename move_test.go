@@ -0,0 +1,75 @@
+/*
+ * move_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestModulePoints tests reading module points file.
+func TestModulePoints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "points")
+	data := "# Test points.\ngate;area_test;120;40.5\n\nwell;area_test2;0;10\n"
+	err := os.WriteFile(path, []byte(data), 0644)
+	if err != nil {
+		t.Fatalf("Unable to write points file: %v", err)
+	}
+	points, err := modulePoints(path)
+	if err != nil {
+		t.Fatalf("Unable to read points: %v", err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("Points number invalid: %d != 2", len(points))
+	}
+	gate := modulePoint{Name: "gate", Area: "area_test", X: 120, Y: 40.5}
+	if points[0] != gate {
+		t.Errorf("Point invalid: %v != %v", points[0], gate)
+	}
+	// No points file.
+	points, err = modulePoints(filepath.Join(t.TempDir(), "points"))
+	if err != nil || len(points) != 0 {
+		t.Errorf("Points without file invalid: %v, %v", points, err)
+	}
+}
+
+// TestMoveWaypoints tests parsing waypoints from move
+// command arguments.
+func TestMoveWaypoints(t *testing.T) {
+	waypoints, err := moveWaypoints(nil, "10", "20", "30.5", "40")
+	if err != nil {
+		t.Fatalf("Unable to parse waypoints: %v", err)
+	}
+	if len(waypoints) != 2 {
+		t.Fatalf("Waypoints number invalid: %d != 2", len(waypoints))
+	}
+	if waypoints[1].X != 30.5 || waypoints[1].Y != 40 {
+		t.Errorf("Waypoint invalid: %v != {30.5 40}", waypoints[1])
+	}
+	_, err = moveWaypoints(nil, "10", "20", "30")
+	if err == nil {
+		t.Errorf("No error for incomplete position")
+	}
+}
@@ -1,7 +1,7 @@
 /*
  * movetar.go
  *
- * Copyright 2023 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2023-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -35,15 +35,15 @@ func moveTarDialog() error {
 	if activeGame == nil {
 		return fmt.Errorf("%s\n", lang.Text("no_game_err"))
 	}
-	if activeGame.ActivePlayer() == nil {
+	if actingPlayer() == nil {
 		return fmt.Errorf("%s\n", lang.Text("no_pc_err"))
 	}
-	tar := activeGame.ActivePlayer().Targets()[0]
+	tar := actingPlayer().Targets()[0]
 	if tar == nil {
 		return fmt.Errorf("%s\n", lang.Text("no_tar_err"))
 	}
 	tarX, tarY := tar.Position()
-	activeGame.ActivePlayer().SetDestPoint(tarX, tarY)
+	actingPlayer().SetDestPoint(tarX, tarY)
 	info := fmt.Sprintf("%s: %fx%f", lang.Text("movetar_info"), tarX, tarY)
 	fmt.Printf("%s\n", info)
 	return nil
@@ -0,0 +1,60 @@
+/*
+ * netstat.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/isangeles/flame/data/res/lang"
+)
+
+// netstatDialog starts CLI dialog that prints game server
+// connection statistics.
+func netstatDialog() error {
+	if server == nil {
+		return fmt.Errorf("%s\n", lang.Text("no_server_err"))
+	}
+	stats := server.Stats()
+	// Address.
+	info := fmt.Sprintf("%s: %s", lang.Text("netstat_address"),
+		server.Address())
+	// State.
+	info += fmt.Sprintf("\n%s: %s", lang.Text("netstat_state"),
+		server.State())
+	// Latency.
+	latency := "-"
+	if stats.Latency > 0 {
+		latency = stats.Latency.Round(time.Microsecond).String()
+	}
+	info += fmt.Sprintf("\n%s: %s", lang.Text("netstat_latency"), latency)
+	// Traffic.
+	info += fmt.Sprintf("\n%s: %d(%d B)", lang.Text("netstat_sent"),
+		stats.MessagesSent, stats.BytesSent)
+	info += fmt.Sprintf("\n%s: %d(%d B)", lang.Text("netstat_received"),
+		stats.MessagesReceived, stats.BytesReceived)
+	// Print.
+	fmt.Printf("%s\n", info)
+	return nil
+}
@@ -1,7 +1,7 @@
 /*
  * newcharacter.go
  *
- * Copyright 2018-2023 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2018-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -37,17 +37,34 @@ import (
 
 const playerIDPrefix = "player_"
 
+var (
+	// Translations for names of characters created
+	// with new character dialog.
+	charNames []flameres.TranslationData
+)
+
 // newCharacterDialog starts CLI dialog to create new playable
 // game character.
-func newCharacterDialog(mod *flame.Module) (flameres.CharacterData, error) {
+// Character can be specified with arguments in the form of
+// '[name] [race] [gender] [str con dex wis int]', in that case
+// the dialog skips all prompts.
+func newCharacterDialog(mod *flame.Module, args ...string) (flameres.CharacterData, error) {
 	var data flameres.CharacterData
 	if mod == nil {
 		return data, fmt.Errorf("no module loaded")
 	}
-	// Character creation dialog
 	name := ""
+	if len(args) > 0 {
+		charData, err := newCharacterArgs(mod, args...)
+		if err != nil {
+			return data, err
+		}
+		name = args[0]
+		data = charData
+	}
+	// Character creation dialog
 	scan := bufio.NewScanner(os.Stdin)
-	for mainAccept := false; !mainAccept; {
+	for mainAccept := len(args) > 0; !mainAccept; {
 		// Name
 		fmt.Printf("%s:", lang.Text("cli_newchar_name"))
 		for scan.Scan() {
@@ -77,22 +94,7 @@ func newCharacterDialog(mod *flame.Module) (flameres.CharacterData, error) {
 			}
 		}
 		// Summary.
-		charID := fmt.Sprintf("%s%s", playerIDPrefix, name)
-		charData := flameres.CharacterData{
-			ID:        charID,
-			Level:     1,
-			Sex:       string(sex),
-			Race:      race,
-			Attitude:  string(character.Friendly),
-			Alignment: string(character.TrueNeutral),
-		}
-		charData.Attributes = flameres.AttributesData{
-			Str: attrs.Str,
-			Con: attrs.Con,
-			Dex: attrs.Dex,
-			Int: attrs.Int,
-			Wis: attrs.Wis,
-		}
+		charData := newCharData(name, race, sex, attrs)
 		fmt.Printf("%s: %s\n", lang.Text("cli_newchar_summary"),
 			charDataDisplayString(charData))
 		fmt.Printf("%s:", lang.Text("cli_accept_dialog"))
@@ -106,6 +108,7 @@ func newCharacterDialog(mod *flame.Module) (flameres.CharacterData, error) {
 	// Add translation for new character name.
 	nameTrans := flameres.TranslationData{data.ID, []string{name}}
 	lang.AddTranslation(nameTrans)
+	charNames = append(charNames, nameTrans)
 	// Add player skills & items from interface config.
 	for _, sid := range mod.Chapter().Conf().StartSkills {
 		skill := flameres.ObjectSkillData{ID: sid}
@@ -118,6 +121,84 @@ func newCharacterDialog(mod *flame.Module) (flameres.CharacterData, error) {
 	return data, nil
 }
 
+// newCharacterArgs creates new playable character data from
+// specified new character command arguments.
+func newCharacterArgs(mod *flame.Module, args ...string) (data flameres.CharacterData, err error) {
+	if len(args) < 3 {
+		return data, fmt.Errorf("%s: %v", lang.Text("invalid_input_err"), args)
+	}
+	// Name.
+	name := args[0]
+	if !charNameValid(name) {
+		return data, fmt.Errorf("%s: %s", lang.Text("cli_newchar_invalid_name_err"),
+			name)
+	}
+	// Race.
+	race := ""
+	for _, r := range flameres.Races {
+		if r.Playable && r.ID == args[1] {
+			race = r.ID
+			break
+		}
+	}
+	if len(race) < 1 {
+		return data, fmt.Errorf("%s: %s", lang.Text("cli_newchar_invalid_value_err"),
+			args[1])
+	}
+	// Gender.
+	sex := character.Gender(args[2])
+	if sex != character.Male && sex != character.Female {
+		return data, fmt.Errorf("%s: %s", lang.Text("cli_newchar_invalid_value_err"),
+			args[2])
+	}
+	// Attributes.
+	attrs := character.Attributes{}
+	if len(args) > 3 {
+		if len(args) < 8 {
+			return data, fmt.Errorf("%s: %v", lang.Text("invalid_input_err"), args[3:])
+		}
+		values := make([]int, 5)
+		points := 0
+		for i, a := range args[3:8] {
+			values[i], err = strconv.Atoi(a)
+			if err != nil || values[i] < 0 {
+				return data, fmt.Errorf("%s: %s", lang.Text("cli_newchar_invalid_value_err"),
+					a)
+			}
+			points += values[i]
+		}
+		if points > mod.Chapter().Conf().StartAttrs {
+			return data, fmt.Errorf(lang.Text("cli_newchar_no_pts_error"))
+		}
+		attrs.Str, attrs.Con, attrs.Dex = values[0], values[1], values[2]
+		attrs.Wis, attrs.Int = values[3], values[4]
+	}
+	return newCharData(name, race, sex, attrs), nil
+}
+
+// newCharData creates data for new playable character with
+// specified name, race, gender and attributes.
+func newCharData(name, race string, sex character.Gender,
+	attrs character.Attributes) flameres.CharacterData {
+	charID := fmt.Sprintf("%s%s", playerIDPrefix, name)
+	data := flameres.CharacterData{
+		ID:        charID,
+		Level:     1,
+		Sex:       string(sex),
+		Race:      race,
+		Attitude:  string(character.Friendly),
+		Alignment: string(character.TrueNeutral),
+	}
+	data.Attributes = flameres.AttributesData{
+		Str: attrs.Str,
+		Con: attrs.Con,
+		Dex: attrs.Dex,
+		Int: attrs.Int,
+		Wis: attrs.Wis,
+	}
+	return data
+}
+
 // raceDialog starts CLI dialog for game character race.
 // Returns character race.
 func raceDialog() string {
@@ -1,7 +1,7 @@
 /*
  * newgame.go
  *
- * Copyright 2018-2021 Dariusz Sikora <dev@isangeles.pl>
+ * Copyright 2018-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -43,7 +43,9 @@ var (
 )
 
 // newGameDialog starts CLI dialog for new game.
-func newGameDialog() error {
+// Playable character can be specified by ID as an argument,
+// in that case the dialog skips the character prompt.
+func newGameDialog(args ...string) error {
 	if mod == nil {
 		return fmt.Errorf("no module loaded")
 	}
@@ -51,8 +53,22 @@ func newGameDialog() error {
 		return fmt.Errorf(lang.Text("cli_newgame_no_chars_err"))
 	}
 	var playerData flameres.CharacterData
+	accept := false
+	if len(args) > 0 {
+		for _, c := range playableChars {
+			if c.ID == args[0] {
+				playerData = c
+				accept = true
+				break
+			}
+		}
+		if !accept {
+			return fmt.Errorf("%s: %s", lang.Text("cli_newgame_char_not_found_err"),
+				args[0])
+		}
+	}
 	scan := bufio.NewScanner(os.Stdin)
-	for accept := false; !accept; {
+	for !accept {
 		fmt.Printf("%s:\n", lang.Text("cli_newgame_chars"))
 		for i, c := range playableChars {
 			fmt.Printf("[%d]%v\n", i, charDataDisplayString(c))
@@ -79,26 +95,33 @@ func newGameDialog() error {
 			accept = true
 		}
 	}
-	activeGame = game.New(mod)
+	setActiveGame(game.New(mod))
 	if server != nil {
 		activeGame.SetServer(server)
-		newCharReq := request.NewChar{lang.Text(playerData.ID), playerData}
+	}
+	return addPlayer(activeGame, playerData)
+}
+
+// addPlayer creates new player character with specified data
+// and adds it to specified game as the active player.
+// In multiplayer the character is created by the server and added
+// to the game after the server response.
+func addPlayer(g *game.Game, data flameres.CharacterData) error {
+	if g.Server() != nil {
+		newCharReq := request.NewChar{lang.Text(data.ID), data}
 		req := request.Request{NewChar: []request.NewChar{newCharReq}}
-		err := activeGame.Server().Send(req)
+		err := g.Server().Send(req)
 		if err != nil {
 			return fmt.Errorf("Unable to send new character request: %v",
 				err)
 		}
 		return nil
 	}
-	player := game.NewPlayer(character.New(playerData), activeGame)
-	activeGame.AddPlayer(player)
-	err := activeGame.SpawnPlayer(player)
+	player := game.NewPlayer(character.New(data), g)
+	g.AddPlayer(player)
+	err := g.SpawnPlayer(player)
 	if err != nil {
 		return fmt.Errorf("Unable to spawn player: %v", err)
 	}
-	if err != nil {
-		return fmt.Errorf("Unable to add player: %v", err)
-	}
 	return nil
 }
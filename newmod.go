@@ -0,0 +1,84 @@
+/*
+ * newmod.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	flamedata "github.com/isangeles/flame/data"
+	flameres "github.com/isangeles/flame/data/res"
+	"github.com/isangeles/flame/data/res/lang"
+
+	"github.com/isangeles/burnsh/config"
+	"github.com/isangeles/burnsh/log"
+)
+
+// newModDialog starts CLI dialog for creating new module
+// in the modules directory.
+// Module ID can be specified as an argument, in that case
+// the dialog skips the module ID prompt.
+func newModDialog(args ...string) error {
+	id := ""
+	if len(args) > 0 {
+		id = args[0]
+		if !modIDValid(id) {
+			return fmt.Errorf("%s: %s", lang.Text("newmod_invalid_id_err"), id)
+		}
+	}
+	scan := bufio.NewScanner(os.Stdin)
+	for len(id) < 1 {
+		fmt.Printf("%s:", lang.Text("newmod_name"))
+		if !scan.Scan() {
+			return fmt.Errorf("unable to read module ID: %v", scan.Err())
+		}
+		input := scan.Text()
+		if !modIDValid(input) {
+			fmt.Printf("%s: %s\n", lang.Text("newmod_invalid_id_err"), input)
+			continue
+		}
+		id = input
+	}
+	path := filepath.Join(config.ModulesPath, id)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("module directory already exists: %s", path)
+	}
+	modConf := make(map[string][]string)
+	modConf["id"] = []string{id}
+	err := flamedata.ExportModuleDir(path, flameres.ModuleData{Config: modConf})
+	if err != nil {
+		return fmt.Errorf("unable to export module: %v", err)
+	}
+	log.Inf.Printf("New module created: %s", path)
+	return nil
+}
+
+// modIDValid checks if specified text is valid
+// module ID.
+func modIDValid(id string) bool {
+	return len(id) > 0 && !strings.ContainsAny(id, " /\\")
+}
@@ -0,0 +1,209 @@
+/*
+ * party.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/isangeles/flame/data/res/lang"
+
+	"github.com/isangeles/burnsh/config"
+	"github.com/isangeles/burnsh/game"
+)
+
+const (
+	partyListArg   = "list"
+	partyAddArg    = "add"
+	partySwitchArg = "switch"
+	partyDoArg     = "do"
+	partyFormArg   = "formation"
+)
+
+var (
+	// Party member running the current command, set
+	// by the party do command.
+	partyPlayer      *game.Player
+	partyPlayerMutex sync.Mutex
+)
+
+// partyCommand handles party command.
+// Lists player characters of the current game, adds new player
+// character, switches the active player, runs command as
+// player specified in arguments, or shows or sets party formation.
+func partyCommand(args ...string) error {
+	if activeGame == nil {
+		return fmt.Errorf("%s", lang.Text("no_game_err"))
+	}
+	if len(args) < 1 {
+		args = []string{partyListArg}
+	}
+	switch {
+	case args[0] == partyListArg:
+		return listParty(activeGame)
+	case args[0] == partyAddArg && len(args) > 1:
+		return addPartyMember(activeGame, args[1])
+	case args[0] == partySwitchArg && len(args) > 1:
+		return switchPlayer(activeGame, args[1])
+	case args[0] == partyDoArg && len(args) > 2:
+		return partyDo(activeGame, args[1], args[2:]...)
+	case args[0] == partyFormArg:
+		return partyFormation(activeGame, args[1:]...)
+	default:
+		return fmt.Errorf("%s: %s", lang.Text("party_invalid_args_err"),
+			strings.Join(args, " "))
+	}
+}
+
+// listParty prints status of all player characters from
+// specified game, the active player is marked with '*'.
+func listParty(g *game.Game) error {
+	if len(g.Players()) < 1 {
+		fmt.Printf("%s\n", lang.Text("party_empty"))
+		return nil
+	}
+	fmt.Printf("%s:\n", lang.Text("party_players"))
+	for _, pc := range g.Players() {
+		mark := " "
+		if pc == g.ActivePlayer() {
+			mark = "*"
+		}
+		areaID := ""
+		if area := g.Chapter().ObjectArea(pc); area != nil {
+			areaID = area.ID()
+		}
+		posX, posY := pc.Position()
+		fmt.Printf("%s%s\t%s\t%s: %d\t%s: %d\t%s: %d\t%s\t%fx%f\n", mark,
+			idSerial(pc.ID(), pc.Serial()), lang.Text(pc.ID()),
+			lang.Text("ob_level"), pc.Level(), lang.Text("ob_health"), pc.Health(),
+			lang.Text("ob_mana"), pc.Mana(), lang.Text(areaID), posX, posY)
+	}
+	return nil
+}
+
+// addPartyMember adds playable character with specified ID
+// to specified game.
+// New player character becomes the active player.
+func addPartyMember(g *game.Game, id string) error {
+	for _, c := range playableChars {
+		if c.ID == id {
+			return addPlayer(g, c)
+		}
+	}
+	return fmt.Errorf("%s: %s", lang.Text("cli_newgame_char_not_found_err"), id)
+}
+
+// switchPlayer sets player character specified by ID and
+// serial value as the active player of specified game.
+func switchPlayer(g *game.Game, idSerial string) error {
+	pc := findPlayer(g, idSerial)
+	if pc == nil {
+		return fmt.Errorf("%s: %s", lang.Text("party_player_not_found_err"), idSerial)
+	}
+	g.SetActivePlayer(pc)
+	return nil
+}
+
+// partyDo runs specified build-in command with specified
+// arguments as player character specified by ID and serial
+// value.
+// Player acts instead of the active player for the time of the
+// command, the active player of the game is not changed, so
+// the party formation still follows the active player.
+func partyDo(g *game.Game, idSerial string, cmd ...string) error {
+	pc := findPlayer(g, idSerial)
+	if pc == nil {
+		return fmt.Errorf("%s: %s", lang.Text("party_player_not_found_err"), idSerial)
+	}
+	partyPlayerMutex.Lock()
+	prev := partyPlayer
+	partyPlayer = pc
+	partyPlayerMutex.Unlock()
+	defer func() {
+		partyPlayerMutex.Lock()
+		partyPlayer = prev
+		partyPlayerMutex.Unlock()
+	}()
+	return execute(strings.TrimPrefix(strings.Join(cmd, " "), CommandPrefix))
+}
+
+// actingPlayer returns player character running the current
+// command, the party member specified by the party do command
+// or the active player of the active game.
+// Returns nil if there is no active game.
+func actingPlayer() *game.Player {
+	partyPlayerMutex.Lock()
+	pc := partyPlayer
+	partyPlayerMutex.Unlock()
+	if pc != nil {
+		return pc
+	}
+	if activeGame == nil {
+		return nil
+	}
+	return activeGame.ActivePlayer()
+}
+
+// findPlayer returns player character from specified game
+// that matches specified ID and serial value, or nil if
+// there is no such player.
+func findPlayer(g *game.Game, idSerial string) *game.Player {
+	for _, pc := range g.Players() {
+		if matchIDSerial(idSerial, pc.ID(), pc.Serial()) {
+			return pc
+		}
+	}
+	return nil
+}
+
+// partyFormation sets formation, and optionally distance between
+// party members, specified in arguments as formation of party in
+// specified game.
+// Prints current formation if no arguments were specified.
+// Formation is saved in the config.
+func partyFormation(g *game.Game, args ...string) error {
+	if len(args) < 1 {
+		fmt.Printf("%s: %s\n", lang.Text("party_formation"), g.Formation())
+		fmt.Printf("%s: %g\n", lang.Text("party_formation_spacing"),
+			g.FormationSpacing())
+		return nil
+	}
+	f, err := game.ParseFormation(args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %s", lang.Text("party_formation_invalid_err"), args[0])
+	}
+	spacing := g.FormationSpacing()
+	if len(args) > 1 {
+		spacing, err = strconv.ParseFloat(args[1], 64)
+		if err != nil || spacing <= 0 {
+			return fmt.Errorf("%s: %s", lang.Text("invalid_input_err"), args[1])
+		}
+	}
+	g.SetFormation(f, spacing)
+	config.Formation = string(f)
+	config.FormationSpacing = spacing
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+ * party_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"testing"
+
+	"github.com/isangeles/burnsh/config"
+	"github.com/isangeles/burnsh/game"
+)
+
+// TestPartySwitch tests switching the active player.
+func TestPartySwitch(t *testing.T) {
+	g := newTestGame("player_test1", "player_test2")
+	setActiveGame(g)
+	defer func() { activeGame = nil }()
+	pc := g.Players()[0]
+	err := partyCommand(partySwitchArg, idSerial(pc.ID(), pc.Serial()))
+	if err != nil {
+		t.Fatalf("Unable to switch player: %v", err)
+	}
+	if g.ActivePlayer() != pc {
+		t.Errorf("Active player invalid: %s != %s", g.ActivePlayer().ID(), pc.ID())
+	}
+	err = partyCommand(partySwitchArg, "player_unknown")
+	if err == nil {
+		t.Errorf("No error for unknown player")
+	}
+}
+
+// TestPartyDo tests running command as party member.
+func TestPartyDo(t *testing.T) {
+	g := newTestGame("player_test1", "player_test2")
+	setActiveGame(g)
+	defer func() { activeGame = nil }()
+	active := g.ActivePlayer()
+	member := g.Players()[0]
+	err := partyCommand(partyDoArg, member.ID(), ChatCmd, "test_message")
+	if err != nil {
+		t.Fatalf("Unable to run command as party member: %v", err)
+	}
+	if g.ActivePlayer() != active {
+		t.Errorf("Active player changed: %s != %s", g.ActivePlayer().ID(),
+			active.ID())
+	}
+	if actingPlayer() != active {
+		t.Errorf("Acting player not restored: %s != %s", actingPlayer().ID(),
+			active.ID())
+	}
+	if len(member.ChatLog().Messages()) != 1 {
+		t.Errorf("Member chat messages number invalid: %d != 1",
+			len(member.ChatLog().Messages()))
+	}
+	if len(active.ChatLog().Messages()) != 0 {
+		t.Errorf("Active player chat messages number invalid: %d != 0",
+			len(active.ChatLog().Messages()))
+	}
+}
+
+// TestPartyFormation tests setting party formation.
+func TestPartyFormation(t *testing.T) {
+	g := newTestGame("player_test1", "player_test2")
+	setActiveGame(g)
+	defer func() { activeGame = nil }()
+	defer func(f string, s float64) {
+		config.Formation, config.FormationSpacing = f, s
+	}(config.Formation, config.FormationSpacing)
+	err := partyCommand(partyFormArg, "wedge", "20")
+	if err != nil {
+		t.Fatalf("Unable to set formation: %v", err)
+	}
+	if g.Formation() != game.FormationWedge {
+		t.Errorf("Formation invalid: %s != %s", g.Formation(), game.FormationWedge)
+	}
+	if g.FormationSpacing() != 20 {
+		t.Errorf("Formation spacing invalid: %f != 20", g.FormationSpacing())
+	}
+	if config.Formation != string(game.FormationWedge) {
+		t.Errorf("Config formation invalid: %s != %s", config.Formation,
+			game.FormationWedge)
+	}
+	err = partyCommand(partyFormArg, "circle")
+	if err == nil {
+		t.Errorf("No error for unknown formation")
+	}
+}
@@ -0,0 +1,138 @@
+/*
+ * profile.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/isangeles/flame/data/res/lang"
+
+	"github.com/isangeles/burnsh/config"
+	"github.com/isangeles/burnsh/data"
+	"github.com/isangeles/burnsh/lineedit"
+	"github.com/isangeles/burnsh/log"
+)
+
+const profileListArg = "list"
+
+// profileCommand handles profile command.
+// Prints current profile, lists all profiles, or switches
+// to profile with name specified as an argument.
+func profileCommand(args ...string) error {
+	if len(args) < 1 {
+		fmt.Printf("%s: %s\n", lang.Text("profile_current"),
+			profileName(config.Profile))
+		fmt.Printf("%s: %s\n", lang.Text("profile_path"), config.Path)
+		return nil
+	}
+	if args[0] == profileListArg {
+		profiles, err := config.Profiles()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s:\n", lang.Text("profile_profiles"))
+		for _, p := range append([]string{""}, profiles...) {
+			mark := " "
+			if p == config.Profile {
+				mark = "*"
+			}
+			fmt.Printf("%s%s\n", mark, profileName(p))
+		}
+		return nil
+	}
+	return switchProfile(args[0])
+}
+
+// switchProfile saves current config and history, and
+// loads config of profile with specified name.
+// Module from the profile config is loaded if there is
+// no active game.
+func switchProfile(name string) error {
+	if name == profileName("") {
+		name = ""
+	}
+	err := config.Save()
+	if err != nil {
+		log.Err.Printf("Unable to save config: %v", err)
+	}
+	saveHistory()
+	modID := config.Module
+	err = config.LoadProfile(name)
+	if err != nil {
+		log.Err.Printf("Unable to load profile config: %v", err)
+	}
+	log.PrintStdOut(config.Debug)
+	if editor != nil {
+		editor.SetHistory(lineedit.NewHistory(config.HistorySize))
+		if _, err := os.Stat(config.HistoryPath()); err == nil {
+			err = editor.History().Load(config.HistoryPath())
+			if err != nil {
+				log.Err.Printf("Unable to load input history: %v", err)
+			}
+		}
+		err = migrateCredentials()
+		if err != nil {
+			log.Err.Printf("Unable to move server credentials to the credentials file: %v", err)
+		}
+	}
+	if config.Module == modID {
+		return nil
+	}
+	if activeGame != nil {
+		fmt.Printf("%s\n", lang.Text("profile_module_restart"))
+		return nil
+	}
+	err = loadModule(config.ModulePath())
+	if err != nil {
+		return fmt.Errorf("unable to load module: %v", err)
+	}
+	err = data.LoadUIData(filepath.Join(config.ModulePath(), data.UIDirPath))
+	if err != nil {
+		return fmt.Errorf("unable to load UI data: %v", err)
+	}
+	return nil
+}
+
+// profileName returns display name of profile with
+// specified name.
+func profileName(name string) string {
+	if len(name) < 1 {
+		return "default"
+	}
+	return name
+}
+
+// completeProfiles returns names of all profiles.
+func completeProfiles(args ...string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	profiles, err := config.Profiles()
+	if err != nil {
+		return nil
+	}
+	return append([]string{profileListArg, profileName("")}, profiles...)
+}
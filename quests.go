@@ -1,7 +1,7 @@
 /*
  * quests.go
  *
- * Copyright 2019-2020 Dariusz Sikora <dev@isangeles.pl>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -34,11 +34,11 @@ func questsDialog() error {
 	if activeGame == nil {
 		return fmt.Errorf("No active game")
 	}
-	if activeGame.ActivePlayer() == nil {
+	if actingPlayer() == nil {
 		return fmt.Errorf("No active PC")
 	}
 	fmt.Printf("%s:\n", lang.Text("quests_list"))
-	for i, q := range activeGame.ActivePlayer().Journal().Quests() {
+	for i, q := range actingPlayer().Journal().Quests() {
 		questInfo := lang.Texts(q.ID())
 		fmt.Printf("[%d]%s\n", i, questInfo[0])
 		if len(questInfo) > 1 {
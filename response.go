@@ -1,7 +1,7 @@
 /*
  * response.go
  *
- * Copyright 2020-2021 Dariusz Sikora <dev@isangeles.pl>
+ * Copyright 2020-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -28,8 +28,6 @@ import (
 	flameres "github.com/isangeles/flame/data/res"
 	"github.com/isangeles/flame/serial"
 
-	"github.com/isangeles/burn"
-
 	"github.com/isangeles/fire/response"
 
 	"github.com/isangeles/burnsh/data/res"
@@ -50,6 +48,12 @@ func handleResponse(resp response.Response) {
 	}
 }
 
+// handleServerState handles change of the game server
+// connection state.
+func handleServerState(cs game.ConnState) {
+	log.Inf.Printf("Game server connection: %s", cs)
+}
+
 // handleUpdateResponse handles update response from the server.
 func handleUpdateResponse(resp response.Update) {
 	flameres.Clear()
@@ -66,8 +70,7 @@ func handleUpdateResponse(resp response.Update) {
 func handleLoadResponse(resp response.Load) {
 	serial.Reset()
 	flameres.Clear()
-	mod = flame.NewModule(resp.Module)
-	burn.Module = mod
-	activeGame = game.New(mod)
-	activeGame.SetServer(server)
+	g := game.New(flame.NewModule(resp.Module))
+	g.SetServer(server)
+	startGame(g)
 }
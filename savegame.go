@@ -1,7 +1,7 @@
 /*
  * savegame.go
  *
- * Copyright 2020-2023 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2020-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -29,81 +29,186 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	flamedata "github.com/isangeles/flame/data"
+	flameres "github.com/isangeles/flame/data/res"
 	"github.com/isangeles/flame/data/res/lang"
 
 	"github.com/isangeles/fire/request"
 
-	"github.com/isangeles/burnsh/config"
+	"github.com/isangeles/burnsh/game"
 	"github.com/isangeles/burnsh/log"
 )
 
 var (
 	SaveExt         = ".savecli"
 	ModuleSavesPath = "burnsh/saves"
+	QuicksaveName   = "quicksave"
+	AutosavePrefix  = "autosave_"
 )
 
+// Current version of the CLI save format.
+const SaveVersion = 1
+
 // Struct for CLI save node.
 type CLISave struct {
-	XMLName xml.Name     `xml:"save"`
-	Name    string       `xml:"name,attr"`
-	Players []PlayerSave `xml:"players>player"`
+	XMLName       xml.Name                   `xml:"save"`
+	Name          string                     `xml:"name,attr"`
+	Version       int                        `xml:"version,attr"`
+	Time          time.Time                  `xml:"time,attr"`
+	Chapter       string                     `xml:"chapter,attr"`
+	Players       []PlayerSave               `xml:"players>player"`
+	History       []string                   `xml:"history>line"`
+	PlayableChars []flameres.CharacterData   `xml:"playable-chars>character"`
+	Translations  []flameres.TranslationData `xml:"translations>translation"`
+	// Error for save that couldn't be read, set by
+	// the saves browser.
+	Err error `xml:"-"`
 }
 
 // Struct for CLI player node.
 type PlayerSave struct {
-	XMLName xml.Name `xml:"player"`
+	XMLName xml.Name      `xml:"player"`
+	ID      string        `xml:"id,attr"`
+	Serial  string        `xml:"serial,attr"`
+	Active  bool          `xml:"active,attr,omitempty"`
+	Level   int           `xml:"level,attr"`
+	Area    string        `xml:"area,attr"`
+	Targets []TargetSave  `xml:"targets>target"`
+	Log     []MessageSave `xml:"log>message"`
+}
+
+// Struct for CLI player target node.
+type TargetSave struct {
+	XMLName xml.Name `xml:"target"`
 	ID      string   `xml:"id,attr"`
 	Serial  string   `xml:"serial,attr"`
 }
 
+// Struct for CLI player log message node.
+type MessageSave struct {
+	XMLName    xml.Name  `xml:"message"`
+	Time       time.Time `xml:"time,attr"`
+	Translated bool      `xml:"translated,attr,omitempty"`
+	Text       string    `xml:",chardata"`
+}
+
 // saveGameDialog starts CLI dialog for saving
 // current game state.
-func saveGameDialog() error {
+// Save name can be specified as an argument, in that
+// case the dialog skips the save name prompt.
+func saveGameDialog(args ...string) error {
 	if activeGame == nil {
 		return fmt.Errorf("no game started")
 	}
-	// CLI.
-	save := new(CLISave)
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
 	scan := bufio.NewScanner(os.Stdin)
-	fmt.Printf("%s:", lang.Text("savegame_save_name"))
-	for scan.Scan() {
-		save.Name = scan.Text()
-		if len(save.Name) > 0 {
-			break
+	for len(name) < 1 {
+		fmt.Printf("%s:", lang.Text("savegame_save_name"))
+		if !scan.Scan() {
+			return fmt.Errorf("unable to read save name: %v", scan.Err())
 		}
+		name = scan.Text()
 	}
-	for _, pc := range activeGame.Players() {
-		pcSave := PlayerSave{
-			ID:     pc.ID(),
-			Serial: pc.Serial(),
+	return saveGameAs(activeGame, name)
+}
+
+// saveGameAs saves specified game under specified name in
+// the module saves directory.
+// In case of game server, CLI state is saved locally and
+// the game is saved by the server.
+func saveGameAs(g *game.Game, name string) error {
+	path := filepath.Join(g.Conf().Path, ModuleSavesPath)
+	// Game server.
+	if g.Server() != nil {
+		err := saveCLI(newCLISave(g, name), path)
+		if err != nil {
+			return fmt.Errorf("unable to save cli: %v", err)
 		}
-		save.Players = append(save.Players, pcSave)
-	}
-	cliSavepath := filepath.Join(mod.Conf().Path, ModuleSavesPath)
-	err := saveCLI(save, cliSavepath)
-	if err != nil {
-		return fmt.Errorf("unable to save cli: %v", err)
-	}
-	// Game.
-	if activeGame.Server() != nil {
-		req := request.Request{Save: []string{save.Name}}
-		err := activeGame.Server().Send(req)
+		req := request.Request{Save: []string{name}}
+		err = g.Server().Send(req)
 		if err != nil {
 			return fmt.Errorf("unable to send save request: %v",
 				err)
 		}
 		return nil
 	}
-	savepath := filepath.Join(config.ModulesPath, save.Name)
-	err = flamedata.ExportModule(savepath, activeGame.Data())
+	return saveGame(g, path, name)
+}
+
+// saveGame saves specified game in save with specified name
+// in directory with specified path.
+// Both CLI state and game module are saved in the same directory.
+// Game state is locked while the module data is collected.
+func saveGame(g *game.Game, dir, name string) error {
+	err := saveCLI(newCLISave(g, name), dir)
+	if err != nil {
+		return fmt.Errorf("unable to save cli: %v", err)
+	}
+	g.Lock()
+	data := g.Data()
+	g.Unlock()
+	modPath := filepath.Join(dir, name+flamedata.ModuleFileExt)
+	err = flamedata.ExportModule(modPath, data)
 	if err != nil {
 		return fmt.Errorf("unable to export module: %v", err)
 	}
 	return nil
 }
 
+// newCLISave creates new CLI save with specified name for
+// specified game and current shell state.
+// Game state is locked while the players state is collected.
+func newCLISave(g *game.Game, name string) *CLISave {
+	players, active := g.Players(), g.ActivePlayer()
+	g.Lock()
+	defer g.Unlock()
+	save := CLISave{
+		Name:          name,
+		Version:       SaveVersion,
+		Time:          time.Now(),
+		Chapter:       g.Chapter().Conf().ID,
+		PlayableChars: playableChars,
+		Translations:  charNames,
+	}
+	for _, pc := range players {
+		pcSave := PlayerSave{
+			ID:     pc.ID(),
+			Serial: pc.Serial(),
+			Active: pc == active,
+			Level:  pc.Level(),
+		}
+		if area := g.Chapter().ObjectArea(pc.Character); area != nil {
+			pcSave.Area = area.ID()
+		}
+		for _, tar := range pc.Targets() {
+			tarSave := TargetSave{ID: tar.ID(), Serial: tar.Serial()}
+			pcSave.Targets = append(pcSave.Targets, tarSave)
+		}
+		for _, m := range pc.Log().Messages() {
+			mSave := MessageSave{
+				Time:       m.Time,
+				Translated: m.Translated,
+				Text:       m.Text,
+			}
+			pcSave.Log = append(pcSave.Log, mSave)
+		}
+		save.Players = append(save.Players, pcSave)
+	}
+	if editor != nil {
+		for _, l := range editor.History().Lines() {
+			if keepInHistory(l) {
+				save.History = append(save.History, l)
+			}
+		}
+	}
+	return &save
+}
+
 // saveCLI saves CLI state in file under specified path.
 func saveCLI(save *CLISave, path string) error {
 	out, err := xml.Marshal(save)
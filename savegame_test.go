@@ -0,0 +1,163 @@
+/*
+ * savegame_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"testing"
+
+	"github.com/isangeles/flame"
+	"github.com/isangeles/flame/area"
+	"github.com/isangeles/flame/character"
+	"github.com/isangeles/flame/data/res"
+	"github.com/isangeles/flame/objects"
+	"github.com/isangeles/flame/serial"
+
+	"github.com/isangeles/burnsh/game"
+)
+
+// newTestGame creates new game with area and player
+// characters with specified IDs.
+func newTestGame(ids ...string) *game.Game {
+	mod := flame.NewModule(res.ModuleData{})
+	a := area.New(res.AreaData{ID: "area_test"})
+	mod.Chapter().AddAreas(a)
+	g := game.New(mod)
+	for _, id := range ids {
+		char := character.New(res.CharacterData{ID: id, Level: 1})
+		a.AddObject(char)
+		g.AddPlayer(game.NewPlayer(char, g))
+	}
+	return g
+}
+
+// TestSaveLoadGame tests saving and loading game.
+func TestSaveLoadGame(t *testing.T) {
+	// Create game.
+	g := newTestGame("player_test1", "player_test2")
+	g.SetActivePlayer(g.Players()[1])
+	g.Players()[0].Log().Add(objects.Message{Text: "test_message"})
+	// Save.
+	dir := t.TempDir()
+	err := saveGame(g, dir, "save_test")
+	if err != nil {
+		t.Fatalf("Unable to save game: %v", err)
+	}
+	// Load.
+	loaded, cliSave, err := loadGame(dir, "save_test")
+	if err != nil {
+		t.Fatalf("Unable to load game: %v", err)
+	}
+	// Test.
+	if len(loaded.Players()) != len(g.Players()) {
+		t.Fatalf("Loaded players number invalid: %d != %d",
+			len(loaded.Players()), len(g.Players()))
+	}
+	for i, pc := range g.Players() {
+		loadedPC := loaded.Players()[i]
+		if loadedPC.ID() != pc.ID() || loadedPC.Serial() != pc.Serial() {
+			t.Errorf("Loaded player invalid: %s#%s != %s#%s",
+				loadedPC.ID(), loadedPC.Serial(), pc.ID(), pc.Serial())
+		}
+	}
+	if cliSave.Version != SaveVersion {
+		t.Errorf("Loaded save version invalid: %d != %d",
+			cliSave.Version, SaveVersion)
+	}
+	messages := loaded.Players()[0].Log().Messages()
+	if len(messages) != 1 || messages[0].Text != "test_message" {
+		t.Errorf("Loaded player log invalid: %v", messages)
+	}
+	if loaded.ActivePlayer() == nil {
+		t.Fatalf("No active player after load")
+	}
+	if loaded.ActivePlayer().ID() != g.ActivePlayer().ID() {
+		t.Errorf("Loaded active player invalid: %s != %s",
+			loaded.ActivePlayer().ID(), g.ActivePlayer().ID())
+	}
+}
+
+// TestLoadGameNoPlayerChar tests loading game with player
+// character missing from the saved module.
+func TestLoadGameNoPlayerChar(t *testing.T) {
+	// Create game.
+	g := newTestGame()
+	char := character.New(res.CharacterData{ID: "player_test", Level: 1})
+	g.AddPlayer(game.NewPlayer(char, g))
+	// Save.
+	dir := t.TempDir()
+	err := saveGame(g, dir, "save_test")
+	if err != nil {
+		t.Fatalf("Unable to save game: %v", err)
+	}
+	// Test.
+	_, _, err = loadGame(dir, "save_test")
+	if err == nil {
+		t.Errorf("No error for missing player character")
+	}
+	if serial.Object(char.ID(), char.Serial()) == nil {
+		t.Errorf("Serials reset by failed load")
+	}
+}
+
+// TestMigrateCLI tests migrating CLI save from
+// the first format version.
+func TestMigrateCLI(t *testing.T) {
+	save := CLISave{
+		Players: []PlayerSave{
+			{ID: "player_test1"},
+			{ID: "player_test2"},
+		},
+	}
+	err := migrateCLI(&save)
+	if err != nil {
+		t.Fatalf("Unable to migrate save: %v", err)
+	}
+	if save.Version != SaveVersion {
+		t.Errorf("Migrated save version invalid: %d != %d",
+			save.Version, SaveVersion)
+	}
+	if !save.Players[0].Active || save.Players[1].Active {
+		t.Errorf("Migrated save active player invalid")
+	}
+	save.Version = SaveVersion + 1
+	err = migrateCLI(&save)
+	if err == nil {
+		t.Errorf("No error for unsupported save version")
+	}
+}
+
+// TestRestoreShell tests restoring shell state from
+// the same save many times.
+func TestRestoreShell(t *testing.T) {
+	defer func() { charNames = nil }()
+	save := CLISave{
+		Translations: []res.TranslationData{{"player_test", []string{"name_test"}}},
+	}
+	// Test.
+	restoreShell(&save)
+	restoreShell(&save)
+	if len(charNames) != 1 {
+		t.Errorf("Character names number invalid: %d != 1", len(charNames))
+	}
+}
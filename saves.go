@@ -0,0 +1,301 @@
+/*
+ * saves.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	flamedata "github.com/isangeles/flame/data"
+	"github.com/isangeles/flame/data/res/lang"
+
+	"github.com/isangeles/burnsh/log"
+)
+
+const (
+	savesListArg   = "list"
+	savesInfoArg   = "info"
+	savesRenameArg = "rename"
+	savesCopyArg   = "copy"
+	savesDeleteArg = "delete"
+)
+
+// savesCommand handles saves command.
+// Lists saves from the module saves directory, or inspects,
+// renames, copies or deletes save specified in arguments.
+// Only local saves are managed, Fire protocol supports
+// only saving and loading of the server saves.
+func savesCommand(args ...string) error {
+	if mod == nil {
+		return fmt.Errorf("no module loaded")
+	}
+	dir := filepath.Join(mod.Conf().Path, ModuleSavesPath)
+	if len(args) < 1 {
+		args = []string{savesListArg}
+	}
+	switch {
+	case args[0] == savesListArg:
+		return listSaves(dir)
+	case args[0] == savesInfoArg && len(args) > 1:
+		return printSaveInfo(dir, args[1])
+	case args[0] == savesRenameArg && len(args) > 2:
+		return renameSave(dir, args[1], args[2])
+	case args[0] == savesCopyArg && len(args) > 2:
+		return copySave(dir, args[1], args[2])
+	case args[0] == savesDeleteArg && len(args) > 1:
+		return deleteSave(dir, args[1])
+	default:
+		return fmt.Errorf("%s: %s", lang.Text("saves_invalid_args_err"),
+			strings.Join(args, " "))
+	}
+}
+
+// listSaves prints all saves from directory with specified
+// path, sorted from the newest one.
+func listSaves(dir string) error {
+	saves, err := readSaves(dir)
+	if err != nil {
+		return err
+	}
+	if len(saves) < 1 {
+		fmt.Printf("%s\n", lang.Text("saves_empty"))
+		return nil
+	}
+	fmt.Printf("%s:\n", lang.Text("loadgame_saves"))
+	for _, s := range saves {
+		if s.Err != nil {
+			fmt.Printf("%s\t%s: %v\n", s.Name, lang.Text("saves_broken"), s.Err)
+			continue
+		}
+		players := make([]string, 0)
+		for _, pcSave := range s.Players {
+			players = append(players, fmt.Sprintf("%s(%s)",
+				lang.Text(pcSave.ID), playerSaveLevel(pcSave)))
+		}
+		fmt.Printf("%s\t%s\t%s\t%s\n", s.Name, s.Time.Format(time.DateTime),
+			s.Chapter, strings.Join(players, ", "))
+	}
+	return nil
+}
+
+// printSaveInfo prints detailed informations about save
+// with specified name from directory with specified path.
+func printSaveInfo(dir, name string) error {
+	save, err := readSave(dir, name)
+	if err != nil {
+		return err
+	}
+	info := fmt.Sprintf("%s: %s", lang.Text("saves_name"), save.Name)
+	info += fmt.Sprintf("\n%s: %s", lang.Text("saves_time"),
+		save.Time.Format(time.DateTime))
+	info += fmt.Sprintf("\n%s: %d", lang.Text("saves_version"), save.Version)
+	info += fmt.Sprintf("\n%s: %s", lang.Text("saves_chapter"), save.Chapter)
+	for _, pcSave := range save.Players {
+		info += fmt.Sprintf("\n%s: %s", lang.Text("saves_player"),
+			lang.Text(pcSave.ID))
+		info += fmt.Sprintf("\n\t%s: %s", lang.Text("ob_id"),
+			idSerial(pcSave.ID, pcSave.Serial))
+		info += fmt.Sprintf("\n\t%s: %s", lang.Text("ob_level"),
+			playerSaveLevel(pcSave))
+		area := lang.Text("saves_unknown")
+		if len(pcSave.Area) > 0 {
+			area = lang.Text(pcSave.Area)
+		}
+		info += fmt.Sprintf("\n\t%s: %s", lang.Text("saves_area"), area)
+		if pcSave.Active {
+			info += fmt.Sprintf("\n\t%s", lang.Text("saves_active_player"))
+		}
+	}
+	fmt.Printf("%s\n", info)
+	return nil
+}
+
+// playerSaveLevel returns level from specified player save,
+// or text for unknown level if the save has no level.
+func playerSaveLevel(save PlayerSave) string {
+	if save.Level < 1 {
+		return lang.Text("saves_unknown")
+	}
+	return fmt.Sprintf("%d", save.Level)
+}
+
+// readSaves reads all saves from directory with specified
+// path.
+// Saves that couldn't be read are returned with the read
+// error and modification time of the save file.
+// Returned saves are sorted by save time, from the newest one.
+func readSaves(dir string) ([]*CLISave, error) {
+	savePattern := fmt.Sprintf(".*%s", SaveExt)
+	files, err := flamedata.DirFilesNames(dir, savePattern)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve save files: %v", err)
+	}
+	saves := make([]*CLISave, 0)
+	for _, f := range files {
+		name := strings.TrimSuffix(f, SaveExt)
+		save, err := readSave(dir, name)
+		if err != nil {
+			log.Err.Printf("Unable to read save: %s: %v", name, err)
+			save = &CLISave{Name: name, Err: err}
+			if info, err := os.Stat(filepath.Join(dir, f)); err == nil {
+				save.Time = info.ModTime()
+			}
+		}
+		saves = append(saves, save)
+	}
+	sort.Slice(saves, func(i, j int) bool {
+		return saves[i].Time.After(saves[j].Time)
+	})
+	return saves, nil
+}
+
+// readSave reads save with specified name from directory
+// with specified path.
+// For saves without save time, the modification time of
+// exported module file is used.
+func readSave(dir, name string) (*CLISave, error) {
+	save, err := loadCLI(filepath.Join(dir, name+SaveExt))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load save: %s: %v", name, err)
+	}
+	save.Name = name
+	if !save.Time.IsZero() {
+		return save, nil
+	}
+	info, err := os.Stat(filepath.Join(dir, name+flamedata.ModuleFileExt))
+	if err != nil {
+		info, err = os.Stat(filepath.Join(dir, name+SaveExt))
+	}
+	if err == nil {
+		save.Time = info.ModTime()
+	}
+	return save, nil
+}
+
+// saveFiles returns paths to all files of save with
+// specified name in directory with specified path.
+func saveFiles(dir, name string) []string {
+	files := []string{filepath.Join(dir, name+SaveExt)}
+	modPath := filepath.Join(dir, name+flamedata.ModuleFileExt)
+	if _, err := os.Stat(modPath); err == nil {
+		files = append(files, modPath)
+	}
+	return files
+}
+
+// renameSave renames save with specified name in directory
+// with specified path.
+func renameSave(dir, name, newName string) error {
+	err := copySave(dir, name, newName)
+	if err != nil {
+		return err
+	}
+	return deleteSave(dir, name)
+}
+
+// copySave creates copy of save with specified name under
+// new name in directory with specified path.
+// Returns an error if any file of the new save already
+// exists.
+func copySave(dir, name, newName string) error {
+	err := checkSaveName(name)
+	if err != nil {
+		return err
+	}
+	err = checkSaveName(newName)
+	if err != nil {
+		return err
+	}
+	for _, ext := range []string{SaveExt, flamedata.ModuleFileExt} {
+		if _, err := os.Stat(filepath.Join(dir, newName+ext)); err == nil {
+			return fmt.Errorf("%s: %s", lang.Text("saves_exists_err"), newName)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, name+SaveExt)); err != nil {
+		return fmt.Errorf("%s: %s", lang.Text("saves_not_found_err"), name)
+	}
+	for _, f := range saveFiles(dir, name) {
+		ext := strings.TrimPrefix(filepath.Base(f), name)
+		err := copyFile(f, filepath.Join(dir, newName+ext))
+		if err != nil {
+			return fmt.Errorf("unable to copy save file: %v", err)
+		}
+	}
+	return nil
+}
+
+// deleteSave removes all files of save with specified
+// name from directory with specified path.
+func deleteSave(dir, name string) error {
+	err := checkSaveName(name)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(dir, name+SaveExt)); err != nil {
+		return fmt.Errorf("%s: %s", lang.Text("saves_not_found_err"), name)
+	}
+	for _, f := range saveFiles(dir, name) {
+		err := os.Remove(f)
+		if err != nil {
+			return fmt.Errorf("unable to remove save file: %v", err)
+		}
+	}
+	return nil
+}
+
+// checkSaveName checks if specified save name is valid.
+// Returns an error if the name contains path separators
+// or parent directory element.
+func checkSaveName(name string) error {
+	if len(name) < 1 || strings.ContainsAny(name, `/\`) ||
+		strings.Contains(name, "..") {
+		return fmt.Errorf("%s: %s", lang.Text("saves_invalid_name_err"), name)
+	}
+	return nil
+}
+
+// copyFile copies file from specified source path to
+// specified destination path.
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("unable to open source file: %v", err)
+	}
+	defer in.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("unable to create destination file: %v", err)
+	}
+	defer out.Close()
+	_, err = io.Copy(out, in)
+	if err != nil {
+		return fmt.Errorf("unable to copy file: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+ * saves_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	flamedata "github.com/isangeles/flame/data"
+)
+
+// TestSavesManage tests copying, renaming and deleting saves.
+func TestSavesManage(t *testing.T) {
+	dir := t.TempDir()
+	save := CLISave{
+		Name:    "save_test",
+		Version: SaveVersion,
+		Chapter: "chapter_test",
+		Players: []PlayerSave{{ID: "player_test", Level: 2}},
+	}
+	err := saveCLI(&save, dir)
+	if err != nil {
+		t.Fatalf("Unable to save cli: %v", err)
+	}
+	// Copy.
+	err = copySave(dir, "save_test", "save_copy")
+	if err != nil {
+		t.Fatalf("Unable to copy save: %v", err)
+	}
+	err = copySave(dir, "save_test", "save_copy")
+	if err == nil {
+		t.Errorf("No error for copy to existing save")
+	}
+	modPath := filepath.Join(dir, "save_module"+flamedata.ModuleFileExt)
+	err = os.WriteFile(modPath, []byte("module_test"), 0644)
+	if err != nil {
+		t.Fatalf("Unable to write module file: %v", err)
+	}
+	err = copySave(dir, "save_test", "save_module")
+	if err == nil {
+		t.Errorf("No error for copy to existing module file")
+	}
+	err = os.Remove(modPath)
+	if err != nil {
+		t.Fatalf("Unable to remove module file: %v", err)
+	}
+	for _, name := range []string{"../save_test", "dir/save_test", ".."} {
+		err = copySave(dir, "save_test", name)
+		if err == nil {
+			t.Errorf("No error for invalid save name: %s", name)
+		}
+	}
+	// Rename.
+	err = renameSave(dir, "save_copy", "save_renamed")
+	if err != nil {
+		t.Fatalf("Unable to rename save: %v", err)
+	}
+	saves, err := readSaves(dir)
+	if err != nil {
+		t.Fatalf("Unable to read saves: %v", err)
+	}
+	if len(saves) != 2 {
+		t.Fatalf("Saves number invalid: %d != 2", len(saves))
+	}
+	renamed, err := readSave(dir, "save_renamed")
+	if err != nil {
+		t.Fatalf("Unable to read renamed save: %v", err)
+	}
+	if renamed.Chapter != save.Chapter || len(renamed.Players) != 1 ||
+		renamed.Players[0].Level != 2 {
+		t.Errorf("Renamed save invalid: %v", renamed)
+	}
+	if renamed.Time.IsZero() {
+		t.Errorf("Renamed save time not set")
+	}
+	// Delete.
+	err = deleteSave(dir, "save_renamed")
+	if err != nil {
+		t.Fatalf("Unable to delete save: %v", err)
+	}
+	err = deleteSave(dir, "save_renamed")
+	if err == nil {
+		t.Errorf("No error for deleting missing save")
+	}
+}
+
+// TestReadSavesBroken tests reading saves directory with
+// broken save.
+func TestReadSavesBroken(t *testing.T) {
+	dir := t.TempDir()
+	save := CLISave{Name: "save_test", Version: SaveVersion}
+	err := saveCLI(&save, dir)
+	if err != nil {
+		t.Fatalf("Unable to save cli: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "save_broken"+SaveExt), []byte("<save"), 0644)
+	if err != nil {
+		t.Fatalf("Unable to write broken save: %v", err)
+	}
+	// Test.
+	saves, err := readSaves(dir)
+	if err != nil {
+		t.Fatalf("Unable to read saves: %v", err)
+	}
+	if len(saves) != 2 {
+		t.Fatalf("Saves number invalid: %d != 2", len(saves))
+	}
+	for _, s := range saves {
+		if s.Name == "save_broken" && s.Err == nil {
+			t.Errorf("No error for broken save")
+		}
+		if s.Name == "save_test" && s.Err != nil {
+			t.Errorf("Save error invalid: %v", s.Err)
+		}
+	}
+}
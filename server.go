@@ -0,0 +1,176 @@
+/*
+ * server.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/isangeles/burn"
+
+	"github.com/isangeles/fire/request"
+
+	"github.com/isangeles/burnsh/config"
+	"github.com/isangeles/burnsh/game"
+	"github.com/isangeles/burnsh/log"
+)
+
+var (
+	// Address of the current game server, in form of
+	// host and port specified on connect.
+	serverAddr string
+	// Path to the file for recording server sessions.
+	recordPath string
+)
+
+// connectServer creates connection to the game server
+// with specified host and port, and sets it as the
+// current server.
+// Connection to the current server is closed only after
+// the new connection is established.
+// The active game, local or from the previous server, is
+// stopped.
+func connectServer(host, port string, tls bool) error {
+	opts, err := serverOptions(tls)
+	if err != nil {
+		return fmt.Errorf("invalid server options: %v", err)
+	}
+	serv, err := game.NewServer(host, port, opts)
+	if err != nil {
+		return err
+	}
+	err = disconnectServer()
+	if err != nil {
+		log.Err.Printf("Unable to disconnect from the current server: %v", err)
+	}
+	stopActiveGame()
+	server = serv
+	serverAddr = net.JoinHostPort(host, port)
+	if len(recordPath) > 0 {
+		err := server.Record(recordPath)
+		if err != nil {
+			log.Err.Printf("Unable to record server session: %v", err)
+		}
+	}
+	server.SetOnResponseFunc(handleResponse)
+	server.SetOnStateChangeFunc(handleServerState)
+	server.SetBatchInterval(time.Duration(config.ServerBatch) * time.Millisecond)
+	server.SetPing(time.Duration(config.ServerPing)*time.Second,
+		time.Duration(config.ServerPingTimeout)*time.Second)
+	log.Inf.Printf("Connected to the game server at: %s", server.Address())
+	return nil
+}
+
+// replaySession sets server that replays session recorded
+// in file with specified path as the current server, and
+// starts the replay.
+// The active game is stopped.
+func replaySession(path string) error {
+	serv, err := game.NewReplayServer(path)
+	if err != nil {
+		return err
+	}
+	stopActiveGame()
+	server = serv
+	serverAddr = path
+	server.SetOnResponseFunc(handleResponse)
+	server.SetOnStateChangeFunc(handleServerState)
+	go func() {
+		err := serv.Replay(true)
+		if err != nil {
+			log.Err.Printf("Unable to replay session: %v", err)
+			return
+		}
+		log.Inf.Printf("Session replay finished: %s", path)
+	}()
+	return nil
+}
+
+// disconnectServer closes connection to the current game
+// server and ends the game from the server.
+// Local module is loaded again, since the current module
+// was updated by the server.
+func disconnectServer() error {
+	if server == nil {
+		return nil
+	}
+	req := request.Request{Close: time.Now().UnixNano()}
+	err := server.Send(req)
+	if err != nil {
+		log.Err.Printf("Unable to send close request: %v", err)
+	}
+	err = server.Close()
+	if err != nil {
+		log.Err.Printf("Unable to close server connection: %v", err)
+	}
+	log.Inf.Printf("Disconnected from the game server at: %s", serverAddr)
+	server = nil
+	serverAddr = ""
+	stopActiveGame()
+	mod = nil
+	burn.Module = nil
+	err = loadModule(config.ModulePath())
+	if err != nil {
+		return fmt.Errorf("unable to load module: %v", err)
+	}
+	return nil
+}
+
+// serverOptions returns server connection options from
+// the config, with specified TLS flag.
+func serverOptions(tls bool) (game.ServerOptions, error) {
+	opts := game.ServerOptions{
+		TLS:            tls,
+		Path:           config.ServerPath,
+		Header:         make(http.Header),
+		Proxy:          config.ServerProxy,
+		ConnectTimeout: time.Duration(config.ServerTimeout) * time.Second,
+	}
+	for _, h := range config.ServerHeaders {
+		name, value, ok := strings.Cut(h, "=")
+		if !ok {
+			return opts, fmt.Errorf("invalid server header: %s", h)
+		}
+		opts.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	if !opts.TLS {
+		return opts, nil
+	}
+	tlsOpts := game.TLSOptions{
+		CAFile:     config.ServerCA,
+		CertFile:   config.ServerCert,
+		KeyFile:    config.ServerKey,
+		ServerName: config.ServerName,
+		Insecure:   config.ServerInsecure,
+	}
+	tlsConf, err := game.NewTLSConfig(tlsOpts)
+	if err != nil {
+		return opts, err
+	}
+	opts.TLSConfig = tlsConf
+	return opts, nil
+}
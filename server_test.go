@@ -0,0 +1,129 @@
+/*
+ * server_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/isangeles/fire/request"
+	"github.com/isangeles/fire/response"
+
+	"github.com/isangeles/burnsh/firetest"
+	"github.com/isangeles/burnsh/game"
+)
+
+// TestConnectServerLocalGame tests stopping the local
+// game on connecting to the Fire server.
+func TestConnectServerLocalGame(t *testing.T) {
+	fire := firetest.NewServer()
+	defer fire.Close()
+	startGame(newTestGame("player_test"))
+	defer stopActiveGame()
+	err := connectServer(fire.Host(), fire.Port(), false)
+	if err != nil {
+		t.Fatalf("Unable to connect to the fake server: %v", err)
+	}
+	defer func() {
+		server.Close()
+		server = nil
+	}()
+	// Test.
+	if activeGame != nil {
+		t.Errorf("Local game not stopped")
+	}
+}
+
+// TestServerLoadResponse tests starting the game from the
+// Fire server load response.
+func TestServerLoadResponse(t *testing.T) {
+	fire := firetest.NewServer()
+	defer fire.Close()
+	err := connectServer(fire.Host(), fire.Port(), false)
+	if err != nil {
+		t.Fatalf("Unable to connect to the fake server: %v", err)
+	}
+	defer func() {
+		server.Close()
+		server = nil
+		activeGame = nil
+	}()
+	// Wait for the first request, so the fake server
+	// registers the connection.
+	err = server.Update()
+	if err != nil {
+		t.Fatalf("Unable to send update request: %v", err)
+	}
+	_, err = fire.WaitRequest(5*time.Second, func(r request.Request) bool { return true })
+	if err != nil {
+		t.Fatalf("No update request: %v", err)
+	}
+	fireGame := newTestGame("player_test")
+	resp := response.Response{Load: response.Load{Save: "save_test", Module: fireGame.Data()}}
+	err = fire.Push(resp)
+	if err != nil {
+		t.Fatalf("Unable to push load response: %v", err)
+	}
+	// Test.
+	timeout := time.After(5 * time.Second)
+	for activeGame == nil {
+		select {
+		case <-timeout:
+			t.Fatalf("Game not started from load response")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if activeGame.Server() != server {
+		t.Errorf("Game server invalid")
+	}
+}
+
+// TestServerConnectFailed tests keeping the current server
+// after failed connection to the new server.
+func TestServerConnectFailed(t *testing.T) {
+	fire := firetest.NewServer()
+	defer fire.Close()
+	err := connectServer(fire.Host(), fire.Port(), false)
+	if err != nil {
+		t.Fatalf("Unable to connect to the fake server: %v", err)
+	}
+	defer func() {
+		server.Close()
+		server = nil
+	}()
+	current := server
+	closed := firetest.NewServer()
+	closed.Close()
+	// Test.
+	err = connectCommand(closed.Host(), closed.Port())
+	if err == nil {
+		t.Errorf("No error for closed server")
+	}
+	if server != current {
+		t.Errorf("Current server replaced after failed connection")
+	}
+	if server.State() != game.Connected {
+		t.Errorf("Current server state invalid: %s != %s", server.State(), game.Connected)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ * servers.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/isangeles/flame/data/res/lang"
+
+	"github.com/isangeles/burnsh/config"
+)
+
+const (
+	serversListArg   = "list"
+	serversAddArg    = "add"
+	serversRemoveArg = "remove"
+	serversTLSArg    = "-tls"
+)
+
+// connectCommand handles connect command.
+// Connects to the bookmarked server with name specified as
+// an argument, or to the server with host and port specified
+// as arguments.
+// Connection to the current server is kept if the connection
+// to the new server fails.
+func connectCommand(args ...string) error {
+	var bookmark config.Bookmark
+	switch len(args) {
+	case 1:
+		bookmarks, err := config.Bookmarks()
+		if err != nil {
+			return err
+		}
+		b, ok := config.FindBookmark(bookmarks, args[0])
+		if !ok {
+			return fmt.Errorf("%s: %s", lang.Text("servers_not_found_err"), args[0])
+		}
+		bookmark = b
+	case 2:
+		bookmark = config.Bookmark{Host: args[0], Port: args[1], TLS: config.ServerTLS}
+	default:
+		return fmt.Errorf("%s: %s", lang.Text("connect_invalid_args_err"),
+			strings.Join(args, " "))
+	}
+	err := connectServer(bookmark.Host, bookmark.Port, bookmark.TLS)
+	if err != nil {
+		return fmt.Errorf("unable to connect to the server: %v", err)
+	}
+	if len(bookmark.Login) > 0 {
+		return loginAs(bookmark.Login)
+	}
+	return nil
+}
+
+// disconnectCommand handles disconnect command.
+func disconnectCommand(args ...string) error {
+	if server == nil {
+		return fmt.Errorf("%s", lang.Text("no_server_err"))
+	}
+	return disconnectServer()
+}
+
+// serversCommand handles servers command.
+// Lists bookmarked servers, or adds or removes bookmark
+// specified in arguments.
+func serversCommand(args ...string) error {
+	if len(args) < 1 {
+		args = []string{serversListArg}
+	}
+	switch {
+	case args[0] == serversListArg:
+		return listBookmarks()
+	case args[0] == serversAddArg && len(args) > 3:
+		return addBookmark(args[1], args[2], args[3], args[4:]...)
+	case args[0] == serversRemoveArg && len(args) > 1:
+		return removeBookmark(args[1])
+	default:
+		return fmt.Errorf("%s: %s", lang.Text("servers_invalid_args_err"),
+			strings.Join(args, " "))
+	}
+}
+
+// listBookmarks prints all bookmarked servers.
+// Current server is marked with '*'.
+func listBookmarks() error {
+	bookmarks, err := config.Bookmarks()
+	if err != nil {
+		return err
+	}
+	if len(bookmarks) < 1 {
+		fmt.Printf("%s\n", lang.Text("servers_empty"))
+		return nil
+	}
+	fmt.Printf("%s:\n", lang.Text("servers_bookmarks"))
+	for _, b := range bookmarks {
+		mark := " "
+		addr := net.JoinHostPort(b.Host, b.Port)
+		if server != nil && addr == serverAddr {
+			mark = "*"
+		}
+		tls := ""
+		if b.TLS {
+			tls = "TLS"
+		}
+		fmt.Printf("%s%s\t%s\t%s\t%s\n", mark, b.Name, addr, tls, b.Login)
+	}
+	return nil
+}
+
+// addBookmark adds bookmark for the server with specified
+// name, host and port.
+// Additional arguments specify login for the server and
+// '-tls' flag.
+func addBookmark(name, host, port string, args ...string) error {
+	bookmarks, err := config.Bookmarks()
+	if err != nil {
+		return err
+	}
+	if _, ok := config.FindBookmark(bookmarks, name); ok {
+		return fmt.Errorf("%s: %s", lang.Text("servers_exists_err"), name)
+	}
+	bookmark := config.Bookmark{Name: name, Host: host, Port: port}
+	for _, a := range args {
+		if a == serversTLSArg {
+			bookmark.TLS = true
+			continue
+		}
+		bookmark.Login = a
+	}
+	return config.SaveBookmarks(append(bookmarks, bookmark))
+}
+
+// removeBookmark removes bookmark with specified name.
+func removeBookmark(name string) error {
+	bookmarks, err := config.Bookmarks()
+	if err != nil {
+		return err
+	}
+	for i, b := range bookmarks {
+		if b.Name == name {
+			bookmarks = append(bookmarks[:i], bookmarks[i+1:]...)
+			return config.SaveBookmarks(bookmarks)
+		}
+	}
+	return fmt.Errorf("%s: %s", lang.Text("servers_not_found_err"), name)
+}
@@ -1,7 +1,7 @@
 /*
  * talk.go
  *
- * Copyright 2019-2025 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -39,23 +39,27 @@ var TalkRange float64 = 50
 
 // talkDialog starts CLI dialog for dialog with
 // current target of active PC.
-func talkDialog() error {
+// Answers can be specified by IDs as arguments, in that
+// case the dialog skips the answer prompts and uses the
+// arguments in order until the dialog is finished or there
+// are no more arguments.
+func talkDialog(args ...string) error {
 	if activeGame == nil {
 		msg := lang.Text("no_game_err")
 		return fmt.Errorf(msg)
 	}
-	if activeGame.ActivePlayer() == nil {
+	if actingPlayer() == nil {
 		msg := lang.Text("no_pc_err")
 		return fmt.Errorf(msg)
 	}
-	if len(activeGame.ActivePlayer().Targets()) < 1 {
+	if len(actingPlayer().Targets()) < 1 {
 		msg := lang.Text("no_tar_err")
 		return fmt.Errorf(msg)
 	}
 	// Range check.
-	tar := activeGame.ActivePlayer().Targets()[0]
+	tar := actingPlayer().Targets()[0]
 	tarX, tarY := tar.Position()
-	pcX, pcY := activeGame.ActivePlayer().Position()
+	pcX, pcY := actingPlayer().Position()
 	if math.Hypot(tarX-pcX, tarY-pcY) > TalkRange {
 		msg := lang.Text("out_of_range_err")
 		return fmt.Errorf(msg)
@@ -67,11 +71,11 @@ func talkDialog() error {
 	if len(tarChar.Dialogs()) < 1 {
 		return fmt.Errorf("no_target_dialogs")
 	}
-	d := tarChar.Dialog(activeGame.ActivePlayer())
-	activeGame.StartDialog(d, activeGame.ActivePlayer())
+	d := tarChar.Dialog(actingPlayer())
+	activeGame.StartDialog(d, actingPlayer())
 	scan := bufio.NewScanner(os.Stdin)
 	// Dialog.
-	for {
+	for argID := 0; ; {
 		fmt.Printf("%s:\n", lang.Text("talk_dialog"))
 		// Dialog stage.
 		if d.Stage() == nil {
@@ -80,17 +84,33 @@ func talkDialog() error {
 		// Dialog stage text.
 		fmt.Printf("[%s]: %s\n", lang.Text(d.Owner().ID()),
 			dialogText(d, d.Stage().ID()))
+		// Select answers.
+		answers := make([]*dialog.Answer, 0)
+		for _, a := range d.Stage().Answers() {
+			if !actingPlayer().MeetReqs(a.Requirements()...) {
+				continue
+			}
+			answers = append(answers, a)
+		}
 		// Answer.
 		var answer *dialog.Answer
-		for answer == nil {
-			// Select answers.
-			answers := make([]*dialog.Answer, 0)
-			for _, a := range d.Stage().Answers() {
-				if !activeGame.ActivePlayer().MeetReqs(a.Requirements()...) {
-					continue
+		if len(args) > 0 {
+			if argID >= len(args) {
+				return nil
+			}
+			for _, a := range answers {
+				if a.ID() == args[argID] {
+					answer = a
+					break
 				}
-				answers = append(answers, a)
 			}
+			if answer == nil {
+				return fmt.Errorf("%s: %s", lang.Text("talk_no_answer_id_err"),
+					args[argID])
+			}
+			argID++
+		}
+		for answer == nil {
 			// Print answers.
 			fmt.Printf("%s:\n", lang.Text("talk_answers"))
 			for i, a := range answers {
@@ -105,16 +125,24 @@ func talkDialog() error {
 				fmt.Printf("%s: %s\n", lang.Text("nan_err"), input)
 				continue
 			}
-			if id < 0 || id > len(d.Stage().Answers())-1 {
+			if id < 0 || id > len(answers)-1 {
 				fmt.Printf("%s\n", lang.Text("talk_no_answer_id_err"))
 				continue
 			}
 			answer = answers[id]
 		}
-		fmt.Printf("[%s]: %s\n", lang.Text(activeGame.ActivePlayer().ID()),
+		fmt.Printf("[%s]: %s\n", lang.Text(actingPlayer().ID()),
 			dialogText(d, answer.ID()))
 		// Dialog progress.
 		activeGame.AnswerDialog(d, answer)
+		if len(args) > 0 {
+			// Trade and training need separate commands in
+			// non-interactive mode.
+			if d.Finished() {
+				break
+			}
+			continue
+		}
 		if d.Trading() {
 			err := tradeDialog()
 			if err != nil {
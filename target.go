@@ -1,7 +1,7 @@
 /*
  * target.go
  *
- * Copyright 2019-2022 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -35,23 +35,37 @@ import (
 
 // targetDialog starts target CLI dialog for
 // active player.
-func targetDialog() error {
+// Target can be specified as an argument in the form
+// of [ID]#[serial], in that case the dialog skips the
+// target prompt.
+func targetDialog(args ...string) error {
 	if activeGame == nil {
 		return fmt.Errorf("%s\n", lang.Text("no_game_err"))
 	}
-	if activeGame.ActivePlayer() == nil {
+	if actingPlayer() == nil {
 		return fmt.Errorf("%s\n", lang.Text("no_pc_err"))
 	}
-	area := activeGame.Chapter().ObjectArea(activeGame.ActivePlayer())
+	area := activeGame.Chapter().ObjectArea(actingPlayer())
 	if area == nil {
 		return fmt.Errorf("no area for active player")
 	}
+	if len(args) > 0 {
+		pcX, pcY := actingPlayer().Position()
+		targets := area.NearObjects(pcX, pcY, actingPlayer().SightRange())
+		for _, t := range targets {
+			if matchIDSerial(args[0], t.ID(), t.Serial()) {
+				actingPlayer().SetTarget(t)
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %s", lang.Text("target_not_found_err"), args[0])
+	}
 	scan := bufio.NewScanner(os.Stdin)
 	var tar effect.Target
 	for tar == nil {
 		fmt.Printf("%s:\n", lang.Text("target_near_targets"))
-		pcX, pcY := activeGame.ActivePlayer().Position()
-		targets := area.NearObjects(pcX, pcY, activeGame.ActivePlayer().SightRange())
+		pcX, pcY := actingPlayer().Position()
+		targets := area.NearObjects(pcX, pcY, actingPlayer().SightRange())
 		if len(targets) < 1 {
 			return nil
 		}
@@ -73,6 +87,6 @@ func targetDialog() error {
 		}
 		tar = targets[id]
 	}
-	activeGame.ActivePlayer().SetTarget(tar)
+	actingPlayer().SetTarget(tar)
 	return nil
 }
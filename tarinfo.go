@@ -1,7 +1,7 @@
 /*
  * tarinfo.go
  *
- * Copyright 2019-2023 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -44,13 +44,13 @@ func targetInfoDialog() error {
 	if activeGame == nil {
 		return fmt.Errorf("%s\n", lang.Text("no_game_err"))
 	}
-	if activeGame.ActivePlayer() == nil {
+	if actingPlayer() == nil {
 		return fmt.Errorf("%s\n", lang.Text("no_pc_err"))
 	}
-	if len(activeGame.ActivePlayer().Targets()) < 1 {
+	if len(actingPlayer().Targets()) < 1 {
 		return fmt.Errorf("%s\n", lang.Text("no_tar_err"))
 	}
-	pcTar := activeGame.ActivePlayer().Targets()[0]
+	pcTar := actingPlayer().Targets()[0]
 	tar, ok := pcTar.(InfoTarget)
 	if !ok {
 		return fmt.Errorf("%s\n", lang.Text("invalid_tar"))
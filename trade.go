@@ -1,7 +1,7 @@
 /*
  * trade.go
  *
- * Copyright 2019-2023 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -35,18 +35,27 @@ import (
 	"github.com/isangeles/flame/item"
 )
 
+const (
+	tradeBuyArg  = "-b"
+	tradeSellArg = "-s"
+)
+
 // tradeDialog starts CLI dialog for trade with
 // current PC target.
-func tradeDialog() error {
+// Items to trade can be specified as arguments in the form of
+// '-b [items to buy ...] -s [items to sell ...]', where each item
+// is specified as [ID]#[serial], in that case the dialog skips
+// the item prompts and the trade confirmation.
+func tradeDialog(args ...string) error {
 	if activeGame == nil {
 		msg := lang.Text("no_game_err")
 		return fmt.Errorf(msg)
 	}
-	if activeGame.ActivePlayer() == nil {
+	if actingPlayer() == nil {
 		msg := lang.Text("no_pc_err")
 		return fmt.Errorf(msg)
 	}
-	tar := activeGame.ActivePlayer().Targets()[0]
+	tar := actingPlayer().Targets()[0]
 	if tar == nil {
 		msg := lang.Text("no_tar_err")
 		return fmt.Errorf(msg)
@@ -56,6 +65,9 @@ func tradeDialog() error {
 		msg := lang.Text("tar_invalid")
 		return fmt.Errorf(msg)
 	}
+	if len(args) > 0 {
+		return tradeArgs(tarChar, args...)
+	}
 	fmt.Printf("%s:\n", lang.Text("trade_buy_items"))
 	buyItems := make([]item.Item, 0)
 	buyValue := 0
@@ -64,7 +76,7 @@ func tradeDialog() error {
 		buyValue += i.Price
 	}
 	fmt.Printf("%s:\n", lang.Text("trade_sell_items"))
-	sellItems := selectSellItems(activeGame.ActivePlayer().Inventory().Items())
+	sellItems := selectSellItems(actingPlayer().Inventory().Items())
 	sellValue := 0
 	for _, it := range sellItems {
 		sellValue += it.Value()
@@ -91,10 +103,82 @@ func tradeDialog() error {
 		return nil
 	}
 	// Trade items.
-	activeGame.Trade(tarChar, activeGame.ActivePlayer(), sellItems, buyItems)
+	activeGame.Trade(tarChar, actingPlayer(), sellItems, buyItems)
+	return nil
+}
+
+// tradeArgs trades with specified character items specified
+// in trade command arguments.
+func tradeArgs(tarChar *character.Character, args ...string) error {
+	buyArgs, sellArgs := make([]string, 0), make([]string, 0)
+	var selArgs *[]string
+	for _, a := range args {
+		switch a {
+		case tradeBuyArg:
+			selArgs = &buyArgs
+		case tradeSellArg:
+			selArgs = &sellArgs
+		default:
+			if selArgs == nil {
+				return fmt.Errorf("%s: %s", lang.Text("invalid_input_err"), a)
+			}
+			*selArgs = append(*selArgs, a)
+		}
+	}
+	buyItems := make([]item.Item, 0)
+	buyValue := 0
+	tarItems := make([]*item.InventoryItem, 0)
+	for _, it := range tarChar.Inventory().Items() {
+		if it.Trade {
+			tarItems = append(tarItems, it)
+		}
+	}
+	for _, a := range buyArgs {
+		var it *item.InventoryItem
+		it, tarItems = argInventoryItem(a, tarItems)
+		if it == nil {
+			return fmt.Errorf("%s: %s", lang.Text("item_not_found_err"), a)
+		}
+		buyItems = append(buyItems, it.Item)
+		buyValue += it.Price
+	}
+	sellItems := make([]item.Item, 0)
+	sellValue := 0
+	pcItems := actingPlayer().Inventory().Items()
+	for _, a := range sellArgs {
+		var it *item.InventoryItem
+		it, pcItems = argInventoryItem(a, pcItems)
+		if it == nil {
+			return fmt.Errorf("%s: %s", lang.Text("item_not_found_err"), a)
+		}
+		sellItems = append(sellItems, it.Item)
+		sellValue += it.Value()
+	}
+	if sellValue < buyValue {
+		return fmt.Errorf(lang.Text("trade_sell_value_small"))
+	}
+	activeGame.Trade(tarChar, actingPlayer(), sellItems, buyItems)
 	return nil
 }
 
+// argInventoryItem returns inventory item from specified items
+// that matches specified [ID]#[serial] argument, or nil if
+// there is no such item.
+// Returns also specified items without the matched item, so
+// the same item is never selected twice.
+func argInventoryItem(arg string, items []*item.InventoryItem) (*item.InventoryItem, []*item.InventoryItem) {
+	for i, it := range items {
+		if !matchIDSerial(arg, it.ID(), it.Serial()) {
+			continue
+		}
+		left := make([]*item.InventoryItem, 0, len(items)-1)
+		left = append(left, items[:i]...)
+		left = append(left, items[i+1:]...)
+		return it, left
+	}
+	return nil, items
+}
+
 // selectSellItems starts dialog for selecting items to
 // sell from specified items.
 func selectSellItems(items []*item.InventoryItem) []item.Item {
@@ -1,7 +1,7 @@
 /*
  * train.go
  *
- * Copyright 2019-2021 Dariusz Sikora <dev@isangeles.pl>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -37,16 +37,18 @@ import (
 
 // tradeDialog starts CLI dialog for train
 // with current PC target.
-func trainDialog() error {
+// Training can be specified by ID as an argument, in that
+// case the dialog skips the training prompt.
+func trainDialog(args ...string) error {
 	if activeGame == nil {
 		msg := lang.Text("no_game_err")
 		return fmt.Errorf(msg)
 	}
-	if activeGame.ActivePlayer() == nil {
+	if actingPlayer() == nil {
 		msg := lang.Text("no_pc_err")
 		return fmt.Errorf(msg)
 	}
-	tar := activeGame.ActivePlayer().Targets()[0]
+	tar := actingPlayer().Targets()[0]
 	if tar == nil {
 		msg := lang.Text("no_tar_err")
 		return fmt.Errorf(msg)
@@ -56,13 +58,22 @@ func trainDialog() error {
 		msg := lang.Text("tar_invalid")
 		return fmt.Errorf(msg)
 	}
+	if len(args) > 0 {
+		for _, t := range tarChar.Trainings() {
+			if t.ID() == args[0] {
+				actingPlayer().Use(t)
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %s", lang.Text("train_not_found_err"), args[0])
+	}
 	t := selectTraining(tarChar.Trainings())
 	if t == nil {
 		msg := lang.Text("train_no_train_sel")
 		fmt.Printf("%s\n", msg)
 		return nil
 	}
-	activeGame.ActivePlayer().Use(t)
+	actingPlayer().Use(t)
 	return nil
 }
 
@@ -1,7 +1,7 @@
 /*
  * useskill.go
  *
- * Copyright 2019-2020 Dariusz Sikora <dev@isangeles.pl>
+ * Copyright 2019-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -35,18 +35,29 @@ import (
 
 // useSkillDialog starts CLI dialog for
 // using skills.
-func useSkillDialog() error {
+// Skill can be specified by ID as an argument, in that
+// case the dialog skips the skill prompt.
+func useSkillDialog(args ...string) error {
 	if activeGame == nil {
 		msg := lang.Text("no_game_err")
 		return fmt.Errorf(msg)
 	}
-	if activeGame.ActivePlayer() == nil {
+	if actingPlayer() == nil {
 		msg := lang.Text("no_pc_err")
 		return fmt.Errorf(msg)
 	}
+	skills := actingPlayer().Skills()
+	if len(args) > 0 {
+		for _, s := range skills {
+			if s.ID() == args[0] {
+				actingPlayer().Use(s)
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %s", lang.Text("skill_not_found_err"), args[0])
+	}
 	// List skills.
 	fmt.Printf("%s:\n", lang.Text("useskill_skills"))
-	skills := activeGame.ActivePlayer().Skills()
 	for i, s := range skills {
 		fmt.Printf("[%d]%s\n", i, lang.Text(s.ID()))
 	}
@@ -68,6 +79,6 @@ func useSkillDialog() error {
 		}
 		skill = skills[id]
 	}
-	activeGame.ActivePlayer().Use(skill)
+	actingPlayer().Use(skill)
 	return nil
 }
@@ -1,7 +1,7 @@
 /*
  * utils.go
  *
- * Copyright 2018-2022 Dariusz Sikora <ds@isangeles.dev>
+ * Copyright 2018-2026 Dariusz Sikora <ds@isangeles.dev>
  *
  * This program is free software; you can redistribute it and/or modify
  * it under the terms of the GNU General Public License as published by
@@ -25,10 +25,13 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	flameres "github.com/isangeles/flame/data/res"
 	"github.com/isangeles/flame/data/res/lang"
 	"github.com/isangeles/flame/req"
+
+	"github.com/isangeles/burn"
 )
 
 // charDataDisplayString returns string with character
@@ -59,3 +62,26 @@ func reqsInfo(reqs ...req.Requirement) string {
 	}
 	return out
 }
+
+// argIDSerial parses specified command argument to the game
+// object ID and serial value.
+// Argument format: [id]#[serial], returns empty serial if
+// argument contains only ID.
+func argIDSerial(arg string) (string, string) {
+	idSerial := strings.SplitN(arg, burn.IDSerialSep, 2)
+	if len(idSerial) < 2 {
+		return arg, ""
+	}
+	return idSerial[0], idSerial[1]
+}
+
+// matchIDSerial checks if specified ID and serial value match
+// specified command argument.
+// Serial value is ignored if argument contains only ID.
+func matchIDSerial(arg, id, serial string) bool {
+	argID, argSerial := argIDSerial(arg)
+	if argID != id {
+		return false
+	}
+	return len(argSerial) < 1 || argSerial == serial
+}